@@ -0,0 +1,58 @@
+package ipv6calc
+
+import (
+	"fmt"
+	"sort"
+)
+
+//Supernet returns prefix of length newMask containing p
+func (p *Prefix) Supernet(newMask uint) (*Prefix, error) {
+	if newMask > p.mask {
+		return nil, fmt.Errorf("supernet /%v cannot be longer than /%v", newMask, p.mask)
+	}
+	s := &Prefix{p.addr, newMask, nil}
+	return s.MakeSubnetAddress(), nil
+}
+
+//Parent returns prefix one bit shorter containing p, nil for ::/0
+func (p *Prefix) Parent() *Prefix {
+	if p.mask == 0 {
+		return nil
+	}
+	s, _ := p.Supernet(p.mask - 1)
+	return s
+}
+
+//sortPrefixes orders prefixes by first address, shorter prefixes first
+func sortPrefixes(ps []*Prefix) {
+	sort.Slice(ps, func(i, j int) bool {
+		if !ps[i].addr.sameBits(&ps[j].addr) {
+			return ps[i].addr.less(&ps[j].addr)
+		}
+		return ps[i].mask < ps[j].mask
+	})
+}
+
+//Aggregate returns minimal list of prefixes covering exactly the same
+//addresses as ps: covered prefixes are dropped and siblings are merged into
+//their parent. Result is sorted, ps is not modified.
+func Aggregate(ps []*Prefix) []*Prefix {
+	sorted := make([]*Prefix, len(ps))
+	for i, p := range ps {
+		sorted[i] = &Prefix{*p.FirstAddressFromSubnet(), p.mask, nil}
+	}
+	sortPrefixes(sorted)
+
+	ret := make([]*Prefix, 0, len(sorted))
+	for _, p := range sorted {
+		if len(ret) > 0 && ret[len(ret)-1].ContainsPrefix(p) {
+			continue
+		}
+		ret = append(ret, p)
+		for len(ret) > 1 && ret[len(ret)-2].IsSiblingOf(ret[len(ret)-1]) {
+			parent := ret[len(ret)-1].Parent()
+			ret = append(ret[:len(ret)-2], parent)
+		}
+	}
+	return ret
+}
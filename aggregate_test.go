@@ -0,0 +1,62 @@
+package ipv6calc
+
+import "testing"
+
+func prefixStrings(ps []*Prefix) []string {
+	ret := make([]string, len(ps))
+	for i, p := range ps {
+		ret[i] = p.CanonicalString()
+	}
+	return ret
+}
+
+func mustPrefixes(t testing.TB, ss ...string) []*Prefix {
+	t.Helper()
+	ret := make([]*Prefix, len(ss))
+	for i, s := range ss {
+		ret[i] = mustPrefix(t, s)
+	}
+	return ret
+}
+
+func TestAggregate(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want []string
+	}{
+		{[]string{}, []string{}},
+		{[]string{"2001:db8::/33", "2001:db8:8000::/33"}, []string{"2001:db8::/32"}},
+		//siblings merge up several levels
+		{[]string{"2001:db8:3::/48", "2001:db8::/47", "2001:db8:2::/48"}, []string{"2001:db8::/46"}},
+		//covered and duplicate prefixes are dropped
+		{[]string{"2001:db8:1::/48", "2001:db8::/32", "2001:db8::/32", "2001:db8:ffff::1/128"}, []string{"2001:db8::/32"}},
+		//host bits are ignored
+		{[]string{"2001:db8::1/127", "2001:db8::2/127"}, []string{"2001:db8::/126"}},
+		//neighbours that are not siblings stay apart
+		{[]string{"2001:db8:1::/48", "2001:db8:2::/48"}, []string{"2001:db8:1::/48", "2001:db8:2::/48"}},
+		{[]string{"8000::/1", "::/1"}, []string{"::/0"}},
+		{[]string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff/128", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe/128"}, []string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe/127"}},
+	}
+	for _, tt := range tests {
+		in := mustPrefixes(t, tt.in...)
+		if got := prefixStrings(Aggregate(in)); !equalStrings(got, tt.want) {
+			t.Errorf("Aggregate(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+		if got := prefixStrings(in); !equalStrings(got, prefixStrings(mustPrefixes(t, tt.in...))) {
+			t.Errorf("Aggregate(%v) modified its input to %v", tt.in, got)
+		}
+	}
+}
+
+func TestSupernet(t *testing.T) {
+	s, err := mustPrefix(t, "2001:db8:1234::/48").Supernet(32)
+	if err != nil || s.CanonicalString() != "2001:db8::/32" {
+		t.Errorf("Supernet(32) = %v, %v", s, err)
+	}
+	if _, err := mustPrefix(t, "2001:db8::/32").Supernet(48); err == nil {
+		t.Errorf("Supernet(48) of a /32 succeeded")
+	}
+	if p := mustPrefix(t, "::/0").Parent(); p != nil {
+		t.Errorf("Parent() of ::/0 = %v", p)
+	}
+}
@@ -0,0 +1,105 @@
+package ipv6calc
+
+import (
+	"errors"
+	"math/big"
+	"math/bits"
+)
+
+//Errors of address arithmetic
+var (
+	ErrOverflow  = errors.New("address overflow past ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff")
+	ErrUnderflow = errors.New("address underflow below ::")
+)
+
+var maxAddrBig = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+//AddrFromBigInt converts number 0...2^128-1 into an address
+func AddrFromBigInt(n *big.Int) (*Addr, error) {
+	if n.Sign() < 0 {
+		return nil, ErrUnderflow
+	}
+	if n.Cmp(maxAddrBig) > 0 {
+		return nil, ErrOverflow
+	}
+	var l, h big.Int
+	l.And(n, new(big.Int).SetUint64(^uint64(0)))
+	h.Rsh(n, 64)
+	return &Addr{high: h.Uint64(), low: l.Uint64()}, nil
+}
+
+//Add returns address n positions further
+func (i6 *Addr) Add(n uint64) (*Addr, error) {
+	nl, carry := bits.Add64(i6.low, n, 0)
+	nh, carry := bits.Add64(i6.high, 0, carry)
+	if carry != 0 {
+		return nil, ErrOverflow
+	}
+	return &Addr{nh, nl, i6.zone}, nil
+}
+
+//Sub returns address n positions back
+func (i6 *Addr) Sub(n uint64) (*Addr, error) {
+	nl, borrow := bits.Sub64(i6.low, n, 0)
+	nh, borrow := bits.Sub64(i6.high, 0, borrow)
+	if borrow != 0 {
+		return nil, ErrUnderflow
+	}
+	return &Addr{nh, nl, i6.zone}, nil
+}
+
+//AddBig adds any, also negative, number to the address
+func (i6 *Addr) AddBig(n *big.Int) (*Addr, error) {
+	sum := i6.BigInt()
+	sum.Add(sum, n)
+	ret, err := AddrFromBigInt(sum)
+	if err != nil {
+		return nil, err
+	}
+	ret.zone = i6.zone
+	return ret, nil
+}
+
+//Cmp compares addresses as 128 bit numbers, returns -1 when i6 is lower than
+//i, 0 when equal and +1 when higher; zones are ignored
+func (i6 *Addr) Cmp(i *Addr) int {
+	if i6.less(i) {
+		return -1
+	}
+	if i.less(i6) {
+		return 1
+	}
+	return 0
+}
+
+//Lsh shifts address bits left by n, bits shifted out are lost
+func (i6 *Addr) Lsh(n uint) *Addr {
+	switch {
+	case n >= 128:
+		return &Addr{0, 0, i6.zone}
+	case n >= 64:
+		return &Addr{i6.low << (n - 64), 0, i6.zone}
+	case n == 0:
+		return &Addr{i6.high, i6.low, i6.zone}
+	}
+	return &Addr{i6.high<<n | i6.low>>(64-n), i6.low << n, i6.zone}
+}
+
+//Rsh shifts address bits right by n, bits shifted out are lost
+func (i6 *Addr) Rsh(n uint) *Addr {
+	switch {
+	case n >= 128:
+		return &Addr{0, 0, i6.zone}
+	case n >= 64:
+		return &Addr{0, i6.high >> (n - 64), i6.zone}
+	case n == 0:
+		return &Addr{i6.high, i6.low, i6.zone}
+	}
+	return &Addr{i6.high >> n, i6.low>>n | i6.high<<(64-n), i6.zone}
+}
+
+//Distance returns b - a, negative when b is before a
+func Distance(a, b *Addr) *big.Int {
+	d := b.BigInt()
+	return d.Sub(d, a.BigInt())
+}
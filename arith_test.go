@@ -0,0 +1,109 @@
+package ipv6calc
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+const maxAddr = "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"
+
+func TestAddSub(t *testing.T) {
+	tests := []struct {
+		addr string
+		n    uint64
+		add  string
+		sub  string
+		err  error
+	}{
+		{"2001:db8::", 1, "2001:db8::1", "2001:db7:ffff:ffff:ffff:ffff:ffff:ffff", nil},
+		{"::ffff:ffff:ffff:ffff", 1, "0:0:0:1::", "::ffff:ffff:ffff:fffe", nil},
+		{"0:0:0:1::", ^uint64(0), "::1:ffff:ffff:ffff:ffff", "::1", nil},
+		{maxAddr, 1, "", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe", ErrOverflow},
+		{"::", 1, "::1", "", ErrUnderflow},
+	}
+	for _, tt := range tests {
+		a := mustAddr(t, tt.addr)
+		got, err := a.Add(tt.n)
+		if tt.add == "" {
+			if !errors.Is(err, tt.err) || got != nil {
+				t.Errorf("%v + %v = %v, %v, want %v", tt.addr, tt.n, got, err, tt.err)
+			}
+		} else if err != nil || got.CanonicalString() != tt.add {
+			t.Errorf("%v + %v = %v, %v, want %v", tt.addr, tt.n, got, err, tt.add)
+		}
+		got, err = a.Sub(tt.n)
+		if tt.sub == "" {
+			if !errors.Is(err, tt.err) || got != nil {
+				t.Errorf("%v - %v = %v, %v, want %v", tt.addr, tt.n, got, err, tt.err)
+			}
+		} else if err != nil || got.CanonicalString() != tt.sub {
+			t.Errorf("%v - %v = %v, %v, want %v", tt.addr, tt.n, got, err, tt.sub)
+		}
+	}
+	z, err := mustAddr(t, "fe80::1%eth0").Add(1)
+	if err != nil || z.Zone() != "eth0" {
+		t.Errorf("Add lost zone: %v, %v", z, err)
+	}
+}
+
+func TestAddBig(t *testing.T) {
+	two64 := new(big.Int).Lsh(big.NewInt(1), 64)
+	tests := []struct {
+		addr string
+		n    *big.Int
+		want string
+		err  error
+	}{
+		{"2001:db8::", two64, "2001:db8:0:1::", nil},
+		{"2001:db8:0:1::", new(big.Int).Neg(two64), "2001:db8::", nil},
+		{"::", maxAddrBig, maxAddr, nil},
+		{"::1", maxAddrBig, "", ErrOverflow},
+		{"::1", big.NewInt(-2), "", ErrUnderflow},
+	}
+	for _, tt := range tests {
+		got, err := mustAddr(t, tt.addr).AddBig(tt.n)
+		if tt.err != nil {
+			if !errors.Is(err, tt.err) {
+				t.Errorf("%v + %v error = %v, want %v", tt.addr, tt.n, err, tt.err)
+			}
+		} else if err != nil || got.CanonicalString() != tt.want {
+			t.Errorf("%v + %v = %v, %v, want %v", tt.addr, tt.n, got, err, tt.want)
+		}
+	}
+}
+
+func TestShiftCmpDistance(t *testing.T) {
+	a := mustAddr(t, "8000::1")
+	tests := []struct {
+		n        uint
+		lsh, rsh string
+	}{
+		{0, "8000::1", "8000::1"},
+		{1, "::2", "4000::"},
+		{64, "0:0:0:1::", "::8000:0:0:0"},
+		{127, "8000::", "::1"},
+		{128, "::", "::"},
+	}
+	for _, tt := range tests {
+		if got := a.Lsh(tt.n).CanonicalString(); got != tt.lsh {
+			t.Errorf("Lsh(%v) = %v, want %v", tt.n, got, tt.lsh)
+		}
+		if got := a.Rsh(tt.n).CanonicalString(); got != tt.rsh {
+			t.Errorf("Rsh(%v) = %v, want %v", tt.n, got, tt.rsh)
+		}
+	}
+	lo, hi := mustAddr(t, "2001:db8::ffff"), mustAddr(t, "2001:db8::1:0")
+	if lo.Cmp(hi) != -1 || hi.Cmp(lo) != 1 || lo.Cmp(mustAddr(t, "2001:db8::ffff%eth0")) != 0 {
+		t.Errorf("Cmp gives wrong order")
+	}
+	if d := Distance(lo, hi); d.Cmp(big.NewInt(1)) != 0 {
+		t.Errorf("Distance(%v, %v) = %v, want 1", lo, hi, d)
+	}
+	if d := Distance(hi, lo); d.Cmp(big.NewInt(-1)) != 0 {
+		t.Errorf("Distance(%v, %v) = %v, want -1", hi, lo, d)
+	}
+	if d := Distance(mustAddr(t, "::"), mustAddr(t, maxAddr)); d.Cmp(maxAddrBig) != 0 {
+		t.Errorf("Distance(::, %v) = %v", maxAddr, d)
+	}
+}
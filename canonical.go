@@ -0,0 +1,68 @@
+package ipv6calc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//ErrInvalidPort is reported by ParseAddrPort for bad or missing port
+var ErrInvalidPort = errors.New("invalid port")
+
+//isIPv4Mapped tells if address is in ::ffff:0:0/96
+func (i6 *Addr) isIPv4Mapped() bool {
+	return i6.high == 0 && i6.low>>32 == 0xffff
+}
+
+//CanonicalString formats address as recommended by RFC 5952: lowercase hex
+//without leading zeros, :: only for the leftmost longest run of two or more
+//zero groups and dotted quad for IPv4-mapped addresses
+func (i6 *Addr) CanonicalString() string {
+	if i6.isIPv4Mapped() {
+		return i6.EmbeddedIPv4String()
+	}
+	s := i6.StringTokensConfig(DefaultHexPrintConfig())
+	s = removeZeroTokens(s)
+	return strings.Join(s, ":") + i6.zoneSuffix()
+}
+
+//CanonicalHostPort formats address with port in RFC 5952 [addr]:port form
+func (i6 *Addr) CanonicalHostPort(port uint16) string {
+	return fmt.Sprintf("[%v]:%v", i6.CanonicalString(), port)
+}
+
+//CanonicalString formats prefix with address in RFC 5952 form, host bits
+//are kept
+func (p *Prefix) CanonicalString() string {
+	return fmt.Sprintf("%v/%v", p.addr.CanonicalString(), p.mask)
+}
+
+//ParseAddrPort parses [addr]:port form
+func ParseAddrPort(s string) (*Addr, uint16, error) {
+	if !strings.HasPrefix(s, "[") {
+		return nil, 0, newParseError(s, 0, ErrInvalidRune)
+	}
+	end := strings.IndexByte(s, ']')
+	if end < 0 {
+		return nil, 0, newParseError(s, len(s), ErrInvalidPort)
+	}
+	i6, err := scanAddr(s, 1, end)
+	if err != nil {
+		return nil, 0, err
+	}
+	pos := end + 1
+	if pos == len(s) || s[pos] != ':' || pos+1 == len(s) {
+		return nil, 0, newParseError(s, pos, ErrInvalidPort)
+	}
+	port := uint32(0)
+	for pos++; pos < len(s); pos++ {
+		if s[pos] < '0' || s[pos] > '9' {
+			return nil, 0, newParseError(s, pos, ErrInvalidPort)
+		}
+		port = port*10 + uint32(s[pos]-'0')
+		if port > 0xFFFF {
+			return nil, 0, newParseError(s, pos, ErrInvalidPort)
+		}
+	}
+	return i6, uint16(port), nil
+}
@@ -0,0 +1,74 @@
+package ipv6calc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCanonicalString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"2001:0db8:0000:0000:0000:0000:0000:0001", "2001:db8::1"},
+		{"2001:DB8::AbCd", "2001:db8::abcd"},
+		{"2001:db8:0:1:1:1:1:1", "2001:db8:0:1:1:1:1:1"},
+		{"2001:db8:0:0:1:0:0:1", "2001:db8::1:0:0:1"},
+		{"2001:0:0:1:0:0:0:1", "2001:0:0:1::1"},
+		{"0:0:1:0:0:0:0:0", "0:0:1::"},
+		{"0:0:0:0:0:0:0:0", "::"},
+		{"0:0:0:0:0:0:0:1", "::1"},
+		{"1:0:0:0:0:0:0:0", "1::"},
+		{"::ffff:c000:201", "::ffff:192.0.2.1"},
+		{"::ffff:0:0", "::ffff:0.0.0.0"},
+		{"64:ff9b::c000:201", "64:ff9b::c000:201"},
+		{"fe80:0::1%eth0", "fe80::1%eth0"},
+	}
+	for _, tt := range tests {
+		if got := mustAddr(t, tt.in).CanonicalString(); got != tt.want {
+			t.Errorf("CanonicalString(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+	if got := mustPrefix(t, "2001:0DB8:0:0::1/64").CanonicalString(); got != "2001:db8::1/64" {
+		t.Errorf("Prefix.CanonicalString() = %v, want 2001:db8::1/64", got)
+	}
+}
+
+func TestAddrPort(t *testing.T) {
+	tests := []struct {
+		in   string
+		port uint16
+		want string
+	}{
+		{"[2001:DB8:0::1]:443", 443, "[2001:db8::1]:443"},
+		{"[::]:0", 0, "[::]:0"},
+		{"[fe80::1%eth0]:65535", 65535, "[fe80::1%eth0]:65535"},
+	}
+	for _, tt := range tests {
+		a, port, err := ParseAddrPort(tt.in)
+		if err != nil || port != tt.port || a.CanonicalHostPort(port) != tt.want {
+			t.Errorf("ParseAddrPort(%q) = %v, %v, %v, want %v", tt.in, a, port, err, tt.want)
+		}
+	}
+	bad := []struct {
+		in     string
+		reason error
+		column int
+	}{
+		{"2001:db8::1:80", ErrInvalidRune, 1},
+		{"[2001:db8::1", ErrInvalidPort, 13},
+		{"[2001:db8::1]", ErrInvalidPort, 14},
+		{"[2001:db8::1]:", ErrInvalidPort, 14},
+		{"[2001:db8::1]80", ErrInvalidPort, 14},
+		{"[2001:db8::1]:65536", ErrInvalidPort, 19},
+		{"[2001:db8::1]:8x", ErrInvalidPort, 16},
+		{"[2001:db8::g]:80", ErrInvalidRune, 12},
+	}
+	for _, tt := range bad {
+		_, _, err := ParseAddrPort(tt.in)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, tt.reason) || pe.Column != tt.column {
+			t.Errorf("ParseAddrPort(%q) error = %v, want %v at column %v", tt.in, err, tt.reason, tt.column)
+		}
+	}
+}
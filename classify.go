@@ -0,0 +1,136 @@
+package ipv6calc
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//go:embed special-registry.txt
+var specialRegistry string
+
+//RegistryFlag is a flag of the special-purpose registry, which can also be
+//marked as not applicable
+type RegistryFlag int8
+
+//Values of RegistryFlag
+const (
+	RegistryFalse RegistryFlag = iota
+	RegistryTrue
+	RegistryNA
+)
+
+func (f RegistryFlag) String() string {
+	switch f {
+	case RegistryTrue:
+		return "true"
+	case RegistryNA:
+		return "n/a"
+	}
+	return "false"
+}
+
+//Class is an entry of the IANA IPv6 Special-Purpose Address Registry
+type Class struct {
+	Prefix            *Prefix
+	Name              string
+	RFC               string
+	Source            RegistryFlag
+	Destination       RegistryFlag
+	Forwardable       RegistryFlag
+	GloballyReachable RegistryFlag
+}
+
+//Registry classifies addresses by the most specific block containing them
+type Registry struct {
+	blocks Trie[*Class]
+}
+
+//defaultRegistry is built from the embedded table
+var defaultRegistry = mustLoadRegistry(specialRegistry)
+
+func mustLoadRegistry(s string) *Registry {
+	r, err := LoadRegistry(strings.NewReader(s))
+	if err != nil {
+		panic(err)
+	}
+	return r
+}
+
+func parseRegistryFlag(s string) (RegistryFlag, error) {
+	switch strings.ToLower(s) {
+	case "true":
+		return RegistryTrue, nil
+	case "false":
+		return RegistryFalse, nil
+	case "n/a":
+		return RegistryNA, nil
+	}
+	return RegistryFalse, fmt.Errorf("invalid flag %q, expected true, false or n/a", s)
+}
+
+//LoadRegistry reads a table of "prefix source destination forwardable
+//global rfc name" lines, like the embedded one returned by
+//DefaultRegistryTable; empty lines and # comments are skipped, errors start
+//with the line number
+func LoadRegistry(rd io.Reader) (*Registry, error) {
+	r := &Registry{}
+	sc := bufio.NewScanner(rd)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) < 7 {
+			return nil, fmt.Errorf("%v: expected prefix, 4 flags, rfc and name", n)
+		}
+		p, err := ParsePrefix(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", n, err)
+		}
+		var flags [4]RegistryFlag
+		for i := range flags {
+			if flags[i], err = parseRegistryFlag(fields[1+i]); err != nil {
+				return nil, fmt.Errorf("%v: %w", n, err)
+			}
+		}
+		c := &Class{p, strings.Join(fields[6:], " "), fields[5], flags[0], flags[1], flags[2], flags[3]}
+		r.blocks.Insert(p, c)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+//DefaultRegistryTable returns the embedded registry table, it can be used as
+//a starting point for a custom one
+func DefaultRegistryTable() string {
+	return specialRegistry
+}
+
+//Classify returns the most specific block containing the address or nil
+//when none does
+func (r *Registry) Classify(a *Addr) *Class {
+	_, c, ok := r.blocks.Lookup(a)
+	if !ok {
+		return nil
+	}
+	return c
+}
+
+//Classify returns the block of the embedded special-purpose registry
+//containing the address or nil, see Registry for a custom table
+func (i6 *Addr) Classify() *Class {
+	return defaultRegistry.Classify(i6)
+}
+
+func (c *Class) String() string {
+	return fmt.Sprintf("%v (%v, %v)", c.Name, c.Prefix.CanonicalString(), c.RFC)
+}
@@ -0,0 +1,52 @@
+package ipv6calc
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		addr   string
+		prefix string
+		flags  [4]RegistryFlag
+	}{
+		{"::1", "::1/128", [4]RegistryFlag{RegistryFalse, RegistryFalse, RegistryFalse, RegistryFalse}},
+		{"2001:5::1", "2001::/23", [4]RegistryFlag{RegistryFalse, RegistryFalse, RegistryFalse, RegistryFalse}},
+		{"2001::1", "2001::/32", [4]RegistryFlag{RegistryTrue, RegistryTrue, RegistryTrue, RegistryNA}},
+		{"2001:1::1", "2001:1::1/128", [4]RegistryFlag{RegistryTrue, RegistryTrue, RegistryTrue, RegistryTrue}},
+		{"2001:db8::1", "2001:db8::/32", [4]RegistryFlag{RegistryFalse, RegistryFalse, RegistryFalse, RegistryFalse}},
+		{"fd00::1", "fc00::/7", [4]RegistryFlag{RegistryTrue, RegistryTrue, RegistryTrue, RegistryFalse}},
+		{"2a00::1", "2000::/3", [4]RegistryFlag{RegistryTrue, RegistryTrue, RegistryTrue, RegistryTrue}},
+	}
+	for _, tt := range tests {
+		c := mustAddr(t, tt.addr).Classify()
+		if c == nil {
+			t.Errorf("Classify(%v) = nil", tt.addr)
+			continue
+		}
+		flags := [4]RegistryFlag{c.Source, c.Destination, c.Forwardable, c.GloballyReachable}
+		if c.Prefix.CanonicalString() != tt.prefix || flags != tt.flags {
+			t.Errorf("Classify(%v) = %v %v, want %v %v", tt.addr, c.Prefix.CanonicalString(), flags, tt.prefix, tt.flags)
+		}
+	}
+	if c := mustAddr(t, "4000::1").Classify(); c != nil {
+		t.Errorf("Classify(4000::1) = %v, want nil", c)
+	}
+}
+
+func TestLoadRegistry(t *testing.T) {
+	r, err := LoadRegistry(strings.NewReader("# comment\n\n2001:db8::/32 true false n/a TRUE RFC0 Our Block\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := r.Classify(mustAddr(t, "2001:db8::1"))
+	if c == nil || c.Name != "Our Block" || c.RFC != "RFC0" || c.Forwardable != RegistryNA || c.GloballyReachable != RegistryTrue {
+		t.Errorf("Classify() = %+v", c)
+	}
+	for _, in := range []string{"2001:db8::/32 true", "2001:db8::/32 yes false false false RFC0 x", "2001:db8::/33x true true true true RFC0 x"} {
+		if _, err := LoadRegistry(strings.NewReader("\n" + in)); err == nil || !strings.HasPrefix(err.Error(), "2: ") {
+			t.Errorf("LoadRegistry(%q) error = %v, want error on line 2", in, err)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+func runAggregate(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+	inputs, err := readInputs(args)
+	if err != nil {
+		return err
+	}
+	ps := make([]*ipv6calc.Prefix, len(inputs))
+	for i, in := range inputs {
+		if ps[i], err = parsePrefix(in); err != nil {
+			return err
+		}
+	}
+	for _, p := range ipv6calc.Aggregate(ps) {
+		fmt.Println(fmtPrefix(p))
+	}
+	return nil
+}
+
+func runSupernet(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	mask, err := parseMask(args[1])
+	if err != nil {
+		return err
+	}
+	s, err := p.Supernet(mask)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmtPrefix(s))
+	return nil
+}
+
+func runExclude(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	inputs, err := readInputs(args[1:])
+	if err != nil {
+		return err
+	}
+	qs := make([]*ipv6calc.Prefix, len(inputs))
+	for i, in := range inputs {
+		if qs[i], err = parsePrefix(in); err != nil {
+			return err
+		}
+	}
+	for _, r := range p.Exclude(qs...) {
+		fmt.Println(fmtPrefix(r))
+	}
+	return nil
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//parseNumber accepts signed decimal, 0x hex, 0o octal and 0b binary numbers
+func parseNumber(s string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid number %q", s)
+	}
+	return n, nil
+}
+
+func runAdd(fs *flag.FlagSet, args []string) error {
+	return addNumber(fs, args, false)
+}
+
+func runSub(fs *flag.FlagSet, args []string) error {
+	return addNumber(fs, args, true)
+}
+
+func addNumber(fs *flag.FlagSet, args []string, negate bool) error {
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	a, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	n, err := parseNumber(args[1])
+	if err != nil {
+		return err
+	}
+	if negate {
+		n.Neg(n)
+	}
+	r, err := a.AddBig(n)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmtAddr(r))
+	return nil
+}
+
+func runDistance(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	a, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := parseAddr(args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Println(ipv6calc.Distance(a, b))
+	return nil
+}
+
+func runCmp(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	a, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := parseAddr(args[1])
+	if err != nil {
+		return err
+	}
+	fmt.Println(a.Cmp(b))
+	return nil
+}
+
+func runLsh(fs *flag.FlagSet, args []string) error {
+	return shift(fs, args, (*ipv6calc.Addr).Lsh)
+}
+
+func runRsh(fs *flag.FlagSet, args []string) error {
+	return shift(fs, args, (*ipv6calc.Addr).Rsh)
+}
+
+func shift(fs *flag.FlagSet, args []string, op func(*ipv6calc.Addr, uint) *ipv6calc.Addr) error {
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	a, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	n, err := strconv.ParseUint(args[1], 10, 8)
+	if err != nil {
+		return fmt.Errorf("invalid shift %q", args[1])
+	}
+	fmt.Println(fmtAddr(op(a, uint(n))))
+	return nil
+}
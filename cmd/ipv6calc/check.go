@@ -0,0 +1,59 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//exitError makes main exit with code, printing err when it is not nil
+type exitError struct {
+	code int
+	err  error
+}
+
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return fmt.Sprintf("exit code %v", e.code)
+	}
+	return e.err.Error()
+}
+
+func runContains(fs *flag.FlagSet, args []string) error {
+	return checkPrefixes(fs, args, (*ipv6calc.Prefix).ContainsPrefix)
+}
+
+func runOverlaps(fs *flag.FlagSet, args []string) error {
+	return checkPrefixes(fs, args, (*ipv6calc.Prefix).Overlaps)
+}
+
+func runSibling(fs *flag.FlagSet, args []string) error {
+	return checkPrefixes(fs, args, (*ipv6calc.Prefix).IsSiblingOf)
+}
+
+//checkPrefixes prints and returns as exit code result of check on two
+//prefixes: 0 when true, 1 when false and 2 for invalid input
+func checkPrefixes(fs *flag.FlagSet, args []string, check func(p, q *ipv6calc.Prefix) bool) error {
+	quiet := fs.Bool("q", false, "do not print the result, only set exit code")
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return &exitError{2, err}
+	}
+	q, err := parsePrefix(args[1])
+	if err != nil {
+		return &exitError{2, err}
+	}
+	ok := check(p, q)
+	if !*quiet {
+		fmt.Println(ok)
+	}
+	if !ok {
+		return &exitError{1, nil}
+	}
+	return nil
+}
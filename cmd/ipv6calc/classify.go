@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//classFlags lists registry flags that are true, flags that are not
+//applicable are listed as name=n/a
+func classFlags(c *ipv6calc.Class) string {
+	ret := make([]string, 0, 4)
+	for _, f := range []struct {
+		flag ipv6calc.RegistryFlag
+		name string
+	}{
+		{c.Source, "source"},
+		{c.Destination, "destination"},
+		{c.Forwardable, "forwardable"},
+		{c.GloballyReachable, "global"},
+	} {
+		switch f.flag {
+		case ipv6calc.RegistryTrue:
+			ret = append(ret, f.name)
+		case ipv6calc.RegistryNA:
+			ret = append(ret, f.name+"=n/a")
+		}
+	}
+	if len(ret) == 0 {
+		return "-"
+	}
+	return strings.Join(ret, ",")
+}
+
+func runClassify(fs *flag.FlagSet, args []string) error {
+	registry := fs.String("registry", "", "read special-purpose registry table from this file instead of the built in one")
+	table := fs.Bool("table", false, "print the built in registry table and exit")
+	args, err := parseArgs(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+	if *table {
+		fmt.Print(ipv6calc.DefaultRegistryTable())
+		return nil
+	}
+	classify := (*ipv6calc.Addr).Classify
+	if len(*registry) > 0 {
+		f, err := os.Open(*registry)
+		if err != nil {
+			return err
+		}
+		r, err := ipv6calc.LoadRegistry(f)
+		f.Close()
+		if err != nil {
+			return fmt.Errorf("%v:%w", *registry, err)
+		}
+		classify = r.Classify
+	}
+	inputs, err := readInputs(args)
+	if err != nil {
+		return err
+	}
+	unknown := 0
+	for _, in := range inputs {
+		a, err := parseAddr(in)
+		if err != nil {
+			return err
+		}
+		c := classify(a)
+		if c == nil {
+			fmt.Printf("%v -\n", in)
+			unknown++
+			continue
+		}
+		fmt.Printf("%v %v %v %v %v\n", in, fmtPrefix(c.Prefix), c.RFC, classFlags(c), c.Name)
+	}
+	if unknown > 0 {
+		return &exitError{1, nil}
+	}
+	return nil
+}
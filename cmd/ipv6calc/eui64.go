@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+func runEUI64(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	mac, err := ipv6calc.ParseMAC(args[1])
+	if err != nil {
+		return err
+	}
+	a, err := p.EUI64(mac)
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmtAddr(a))
+	return nil
+}
+
+func runMAC(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+	inputs, err := readInputs(args)
+	if err != nil {
+		return err
+	}
+	missing := 0
+	for _, in := range inputs {
+		a, err := parseAddr(in)
+		if err != nil {
+			return err
+		}
+		mac, ok := a.MAC()
+		if !ok {
+			fmt.Printf("%v -\n", in)
+			missing++
+			continue
+		}
+		fmt.Printf("%v %v\n", in, mac)
+	}
+	if missing > 0 {
+		return &exitError{1, nil}
+	}
+	return nil
+}
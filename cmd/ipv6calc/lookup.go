@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//readTable reads "prefix [value]" lines into a trie, empty lines and
+//# comments are skipped
+func readTable(name string) (*ipv6calc.Trie[string], error) {
+	t := &ipv6calc.Trie[string]{}
+	err := scanLines(name, func(n int, line string) error {
+		fields := strings.Fields(line)
+		p, err := parsePrefix(fields[0])
+		if err != nil {
+			return err
+		}
+		t.Insert(p, strings.Join(fields[1:], " "))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func runLookup(fs *flag.FlagSet, args []string) error {
+	all := fs.Bool("all", false, "print all matching prefixes, not only the longest")
+	args, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	t, err := readTable(args[0])
+	if err != nil {
+		return err
+	}
+	inputs, err := readInputs(args[1:])
+	if err != nil {
+		return err
+	}
+	missing := 0
+	for _, in := range inputs {
+		p, err := parsePrefix(in)
+		if err != nil {
+			return err
+		}
+		matches := t.Covering(p)
+		if len(matches) == 0 {
+			fmt.Printf("%v -\n", in)
+			missing++
+			continue
+		}
+		if !*all {
+			matches = matches[len(matches)-1:]
+		}
+		for _, m := range matches {
+			fmt.Println(strings.TrimSpace(fmt.Sprintf("%v %v %v", in, fmtPrefix(m.Prefix), m.Value)))
+		}
+	}
+	if missing > 0 {
+		return &exitError{1, nil}
+	}
+	return nil
+}
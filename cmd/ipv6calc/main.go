@@ -0,0 +1,468 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(fs *flag.FlagSet, args []string) error
+}
+
+type usageError struct {
+	msg string
+}
+
+func (e *usageError) Error() string {
+	return e.msg
+}
+
+func usageErrorf(format string, a ...interface{}) error {
+	return &usageError{fmt.Sprintf(format, a...)}
+}
+
+var errFlags = errors.New("invalid flags")
+
+var strict = flag.Bool("strict", false, "reject zone identifiers on addresses that are not link-local")
+var upcase = flag.Bool("upper", false, "print hex digits in uppercase")
+var pad = flag.Bool("pad", false, "pad every group to 4 hex digits")
+var markers = flag.String("markers", "<>[]{}()", "pairs of characters marking start and end of exposed digits, one pair per range")
+
+//printCfg is built from global flags in main
+var printCfg = ipv6calc.DefaultHexPrintConfig()
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"info", "<prefix>", "show address, subnet, first and last address of a prefix", runInfo},
+		{"first", "<prefix>", "print the first address of a prefix", runFirst},
+		{"last", "<prefix>", "print the last address of a prefix", runLast},
+		{"next", "[-n count] <prefix>", "print the prefixes following a prefix", runNext},
+		{"prev", "[-n count] <prefix>", "print the prefixes preceding a prefix", runPrev},
+		{"expose", "[-n count] <prefix> [start end]...", "mark bit ranges start..end, or the bits changing over the next count prefixes", runExpose},
+		{"mask", "<length|netmask|wildcard>", "print the prefix length, netmask and hostmask", runMask},
+		{"xor", "<addr> <addr>", "xor two addresses and report the range of differing bits", runXor},
+		{"count", "<prefix> <length>", "print how many prefixes of the given length fit in a prefix", runCount},
+		{"split", "[-first n] [-every k | -nth n] <prefix> <length>", "print child prefixes of the given length", runSplit},
+		{"supernet", "<prefix> <length>", "print the prefix of the given length containing a prefix", runSupernet},
+		{"aggregate", "[prefix...]", "merge prefixes into the minimal equivalent list, reading stdin without arguments", runAggregate},
+		{"exclude", "<prefix> [prefix...]", "remove prefixes from a prefix and print what is left, reading excluded prefixes from stdin without arguments", runExclude},
+		{"cidr", "<first-last>", "convert an address range into the minimal list of prefixes", runCidr},
+		{"range", "<prefix>", "print a prefix as first-last address range", runRange},
+		{"set", "[-size] <union|intersect|diff> <file> <file>", "combine two lists of prefixes and ranges (\"-\" reads stdin) and print the result as prefixes", runSet},
+		{"lookup", "[-all] <table> [addr|prefix...]", "longest prefix match against a file of \"prefix [value]\" lines, reading stdin without addresses", runLookup},
+		{"contains", "[-q] <prefix> <addr|prefix>", "check if the prefix contains an address or prefix, exit code 1 when not", runContains},
+		{"overlaps", "[-q] <prefix> <prefix>", "check if two prefixes overlap, exit code 1 when not", runOverlaps},
+		{"sibling", "[-q] <prefix> <prefix>", "check if two prefixes are halves of the same parent, exit code 1 when not", runSibling},
+		{"add", "<addr> <number>", "add a number (decimal or 0x hex, may be negative) to an address", runAdd},
+		{"sub", "<addr> <number>", "subtract a number from an address", runSub},
+		{"distance", "<addr> <addr>", "print the second address minus the first", runDistance},
+		{"cmp", "<addr> <addr>", "compare addresses, print -1, 0 or 1", runCmp},
+		{"lsh", "<addr> <bits>", "shift address bits left", runLsh},
+		{"rsh", "<addr> <bits>", "shift address bits right", runRsh},
+		{"solicited", "[-in prefix [-first n]] <addr>", "print solicited-node multicast address of an address, or with -in addresses of the prefix mapping to a solicited-node address", runSolicited},
+		{"eui64", "<prefix> <mac>", "print SLAAC address built from a MAC address and a /64 prefix", runEUI64},
+		{"mac", "[addr...]", "print MAC addresses recovered from EUI-64 interface IDs, reading stdin without arguments", runMAC},
+		{"stable", "-secret key [-iface name] [-network id] [-dad n] [-linux [-mac mac]] <prefix>", "print RFC 7217 stable-privacy address in a /64 prefix", runStable},
+		{"classify", "[-registry file] [-table] [addr...]", "print IANA special-purpose registry block and flags of addresses, reading stdin without arguments", runClassify},
+		{"reverse", "[input...]", "print ip6.arpa name of an address or zones of a prefix, or parse ip6.arpa names back, reading stdin without arguments", runReverse},
+		{"ptrzone", "[-ns list] [-mbox name] [-serial n] [-ttl s] [-dir path] <prefix> <mapping>", "generate BIND zone files with PTR records from a file of address and host name lines", runPTRZone},
+		{"normalize", "[-check] [input...]", "rewrite addresses and prefixes into RFC 5952 canonical form, reading stdin without arguments", runNormalize},
+	}
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func usage() {
+	w := flag.CommandLine.Output()
+	fmt.Fprintf(w, "usage: ipv6calc [flags] <command> [arguments]\n\ncommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nflags:\n")
+	flag.PrintDefaults()
+	fmt.Fprintf(w, "\nrun \"ipv6calc <command> -h\" for help on a command\n")
+}
+
+func main() {
+	flag.Usage = usage
+	flag.Parse()
+	if flag.NArg() < 1 {
+		usage()
+		os.Exit(2)
+	}
+	m := []rune(*markers)
+	if len(m) == 0 || len(m)%2 != 0 {
+		fmt.Fprintf(os.Stderr, "ipv6calc: -markers needs pairs of characters, got %q\n", *markers)
+		os.Exit(2)
+	}
+	printCfg = ipv6calc.HexPrintConfig{Upcase: *upcase, LeadingZeros: *pad, ExposeStartChar: m[0], ExposeEndChar: m[1]}
+
+	c := findCommand(flag.Arg(0))
+	if c == nil {
+		fmt.Fprintf(os.Stderr, "ipv6calc: unknown command %q\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet(c.name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: ipv6calc %s %s\n\n%s\n", c.name, c.args, c.summary)
+		fs.PrintDefaults()
+	}
+	err := c.run(fs, flag.Args()[1:])
+	if err == nil {
+		return
+	}
+	if err == flag.ErrHelp {
+		os.Exit(0)
+	}
+	var ee *exitError
+	if errors.As(err, &ee) {
+		if ee.err != nil {
+			fmt.Fprintf(os.Stderr, "ipv6calc %s: %v\n", c.name, ee.err)
+		}
+		os.Exit(ee.code)
+	}
+	var ue *usageError
+	if errors.As(err, &ue) {
+		fmt.Fprintf(os.Stderr, "ipv6calc %s: %v\n", c.name, err)
+		fs.Usage()
+		os.Exit(2)
+	}
+	if err == errFlags {
+		//flag package already reported the problem
+		os.Exit(2)
+	}
+	fmt.Fprintf(os.Stderr, "ipv6calc %s: %v\n", c.name, err)
+	var pe *ipv6calc.ParseError
+	if errors.As(err, &pe) {
+		//point at the offending character
+		fmt.Fprintf(os.Stderr, "  %s\n  %s^\n", pe.Input, strings.Repeat(" ", pe.Column-1))
+	}
+	os.Exit(1)
+}
+
+//parseArgs parses flags and checks that between min and max positional
+//arguments are left, negative max means no limit
+func parseArgs(fs *flag.FlagSet, args []string, min, max int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil, err
+		}
+		return nil, errFlags
+	}
+	if fs.NArg() < min {
+		return nil, usageErrorf("not enough arguments")
+	}
+	if max >= 0 && fs.NArg() > max {
+		return nil, usageErrorf("too many arguments")
+	}
+	return fs.Args(), nil
+}
+
+func parsePrefix(s string) (*ipv6calc.Prefix, error) {
+	if *strict {
+		return ipv6calc.ParsePrefixStrict(s)
+	}
+	return ipv6calc.ParsePrefix(s)
+}
+
+func parseAddr(s string) (*ipv6calc.Addr, error) {
+	if *strict {
+		return ipv6calc.ParseAddrStrict(s)
+	}
+	return ipv6calc.ParseAddr(s)
+}
+
+//readInputs returns arguments, or non empty lines of stdin when there are no
+//arguments
+func readInputs(args []string) ([]string, error) {
+	if len(args) > 0 {
+		return args, nil
+	}
+	ret := make([]string, 0)
+	sc := bufio.NewScanner(os.Stdin)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if len(line) > 0 {
+			ret = append(ret, line)
+		}
+	}
+	return ret, sc.Err()
+}
+
+//scanLines calls fn with number and text of every line of file name (- is
+//stdin); # comments are stripped, space is trimmed and empty lines are
+//skipped. Errors are prefixed with file name and line number.
+func scanLines(name string, fn func(n int, line string) error) error {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		line = strings.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		if err := fn(n, line); err != nil {
+			return fmt.Errorf("%v:%v: %w", name, n, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return fmt.Errorf("%v: %w", name, err)
+	}
+	return nil
+}
+
+func fmtAddr(a *ipv6calc.Addr) string {
+	return a.StringConfig(printCfg)
+}
+
+func fmtPrefix(p *ipv6calc.Prefix) string {
+	return p.StringConfig(printCfg)
+}
+
+func parseBit(s string) (uint, error) {
+	b, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || b > 127 {
+		return 0, fmt.Errorf("invalid bit number %q", s)
+	}
+	return uint(b), nil
+}
+
+func runInfo(fs *flag.FlagSet, args []string) error {
+	subnets := fs.Uint("subnets", 64, "also report how many prefixes of this length fit")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	addr := p.Addr()
+	hex := addr.Hex()
+	if *upcase {
+		hex = strings.ToUpper(hex)
+	}
+	fmt.Printf("%-8s %v\n", "prefix:", fmtPrefix(p))
+	fmt.Printf("%-8s %v/%v\n", "subnet:", fmtAddr(p.FirstAddressFromSubnet()), p.Mask())
+	fmt.Printf("%-8s %v\n", "address:", addr.LongStringConfig(printCfg))
+	fmt.Printf("%-8s %v\n", "netmask:", fmtAddr(p.AddrMask()))
+	fmt.Printf("%-8s %v\n", "first:", fmtAddr(p.FirstAddressFromSubnet()))
+	fmt.Printf("%-8s %v\n", "last:", fmtAddr(p.LastAddressFromSubnet()))
+	fmt.Printf("%-8s %v\n", "size:", p.Size().Human())
+	if n, err := p.NumSubnets(*subnets); err == nil && *subnets > p.Mask() {
+		fmt.Printf("%-8s %v\n", fmt.Sprintf("/%vs:", *subnets), n.Human())
+	}
+	fmt.Printf("%-8s %v\n", "hex:", hex)
+	fmt.Printf("%-8s %v\n", "decimal:", addr.BigInt())
+	if c := addr.Classify(); c != nil {
+		fmt.Printf("%-8s %v (%v, %v)\n", "class:", c.Name, fmtPrefix(c.Prefix), c.RFC)
+		fmt.Printf("%-8s %v\n", "flags:", classFlags(c))
+	}
+	if mac, ok := addr.MAC(); ok && !addr.IsMulticast() {
+		fmt.Printf("%-8s %v (EUI-64)\n", "mac:", mac)
+	}
+	if addr.IsMulticast() {
+		printMulticast(&addr)
+	}
+	return nil
+}
+
+func runFirst(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmtAddr(p.FirstAddressFromSubnet()))
+	return nil
+}
+
+func runLast(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(fmtAddr(p.LastAddressFromSubnet()))
+	return nil
+}
+
+func runNext(fs *flag.FlagSet, args []string) error {
+	return walkPrefixes(fs, args, (*ipv6calc.Prefix).NextPrefix, "no next prefix")
+}
+
+func runPrev(fs *flag.FlagSet, args []string) error {
+	return walkPrefixes(fs, args, (*ipv6calc.Prefix).PrevPrefix, "no previous prefix")
+}
+
+func walkPrefixes(fs *flag.FlagSet, args []string, step func(*ipv6calc.Prefix) *ipv6calc.Prefix, endMsg string) error {
+	count := fs.Uint("n", 1, "number of prefixes to print")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	for i := uint(0); i < *count; i++ {
+		p = step(p)
+		if p == nil {
+			return errors.New(endMsg)
+		}
+		fmt.Println(fmtPrefix(p))
+	}
+	return nil
+}
+
+func runExpose(fs *flag.FlagSet, args []string) error {
+	count := fs.Uint("n", 0, "also print this many following prefixes")
+	args, err := parseArgs(fs, args, 1, -1)
+	if err != nil {
+		return err
+	}
+	if len(args)%2 == 0 {
+		return usageErrorf("both start and end bit are required")
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	p.MakeSubnetAddress()
+
+	prefixes := []*ipv6calc.Prefix{p}
+	cum := &ipv6calc.Addr{}
+	for i := uint(0); i < *count; i++ {
+		np := prefixes[len(prefixes)-1].NextPrefix()
+		if np == nil {
+			break
+		}
+		a, b := prefixes[len(prefixes)-1].Addr(), np.Addr()
+		cum = cum.CummulativeXor(&a, &b)
+		prefixes = append(prefixes, np)
+	}
+
+	m := []rune(*markers)
+	ranges := make([]ipv6calc.ExposeRange, 0, len(args)/2)
+	for i := 1; i < len(args); i += 2 {
+		start, err := parseBit(args[i])
+		if err != nil {
+			return err
+		}
+		end, err := parseBit(args[i+1])
+		if err != nil {
+			return err
+		}
+		if start > end {
+			return fmt.Errorf("start bit %v is after end bit %v", start, end)
+		}
+		//marker pairs are reused when there are more ranges than pairs
+		pair := (len(ranges) * 2) % len(m)
+		ranges = append(ranges, ipv6calc.ExposeRange{StartBit: start, EndBit: end, StartChar: m[pair], EndChar: m[pair+1]})
+	}
+	if len(ranges) == 0 {
+		if *count == 0 {
+			return usageErrorf("start and end bit are required without -n")
+		}
+		start, end := cum.BitsRange()
+		ranges = append(ranges, ipv6calc.ExposeRange{StartBit: start, EndBit: end})
+	}
+	for _, v := range prefixes {
+		if len(ranges) == 1 {
+			fmt.Println(v.ExposeStringConfig(ranges[0].StartBit, ranges[0].EndBit, printCfg))
+		} else {
+			a := v.Addr()
+			fmt.Printf("%v/%v\n", a.MultiExposeRangeStringConfig(ranges, printCfg), v.Mask())
+		}
+	}
+	return nil
+}
+
+func runMask(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	var mask uint
+	if strings.Contains(args[0], ":") {
+		p, err := parsePrefix("::/" + args[0])
+		if err != nil {
+			return err
+		}
+		mask = p.Mask()
+	} else if mask, err = parseMask(args[0]); err != nil {
+		return err
+	}
+	m, err := ipv6calc.AddrFromMask(mask)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("%-9s /%v\n", "length:", mask)
+	fmt.Printf("%-9s %v\n", "netmask:", fmtAddr(&m))
+	fmt.Printf("%-9s %v\n", "hostmask:", fmtAddr(m.Neg()))
+	return nil
+}
+
+func runXor(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	a, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	b, err := parseAddr(args[1])
+	if err != nil {
+		return err
+	}
+	x := a.Xor(b)
+	fmt.Printf("%-5s %v\n", "xor:", fmtAddr(x))
+	fmt.Printf("%-5s %v\n", "long:", x.LongStringConfig(printCfg))
+	if x.High() == 0 && x.Low() == 0 {
+		fmt.Printf("%-5s none\n", "bits:")
+	} else {
+		start, stop := x.BitsRange()
+		fmt.Printf("%-5s %v-%v\n", "bits:", start, stop)
+	}
+	return nil
+}
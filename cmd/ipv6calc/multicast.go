@@ -0,0 +1,34 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//printMulticast prints decoded multicast fields for info
+func printMulticast(a *ipv6calc.Addr) {
+	m, err := a.Multicast()
+	if err != nil {
+		fmt.Printf("%-8s invalid: %v\n", "mcast:", err)
+		return
+	}
+	bit := func(f uint8) int {
+		if m.Flags&f != 0 {
+			return 1
+		}
+		return 0
+	}
+	fmt.Printf("%-8s R=%v P=%v T=%v\n", "mflags:", bit(ipv6calc.MulticastEmbeddedRP), bit(ipv6calc.MulticastPrefix), bit(ipv6calc.MulticastTransient))
+	fmt.Printf("%-8s %x (%v)\n", "scope:", m.Scope, ipv6calc.ScopeName(m.Scope))
+	fmt.Printf("%-8s %#x\n", "group:", m.GroupID)
+	switch {
+	case m.RP != nil:
+		fmt.Printf("%-8s %v\n", "mprefix:", fmtPrefix(m.Prefix))
+		fmt.Printf("%-8s %v\n", "rp:", fmtAddr(m.RP))
+	case m.SourceSpecific():
+		fmt.Printf("%-8s source-specific\n", "mprefix:")
+	case m.Prefix != nil:
+		fmt.Printf("%-8s %v\n", "mprefix:", fmtPrefix(m.Prefix))
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//canonicalize returns RFC 5952 form of an address, prefix or [addr]:port
+func canonicalize(s string) (string, error) {
+	if strings.HasPrefix(s, "[") {
+		a, port, err := ipv6calc.ParseAddrPort(s)
+		if err != nil {
+			return "", err
+		}
+		return a.CanonicalHostPort(port), nil
+	}
+	if strings.Contains(s, "/") {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return "", err
+		}
+		return p.CanonicalString(), nil
+	}
+	a, err := parseAddr(s)
+	if err != nil {
+		return "", err
+	}
+	return a.CanonicalString(), nil
+}
+
+func runNormalize(fs *flag.FlagSet, args []string) error {
+	check := fs.Bool("check", false, "fail when any input is not canonical")
+	args, err := parseArgs(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+	inputs, err := readInputs(args)
+	if err != nil {
+		return err
+	}
+	invalid, changed := 0, 0
+	for _, in := range inputs {
+		c, err := canonicalize(in)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ipv6calc normalize: %v\n", err)
+			invalid++
+			continue
+		}
+		if c != in {
+			fmt.Fprintf(os.Stderr, "not canonical: %s -> %s\n", in, c)
+			changed++
+		}
+		fmt.Println(c)
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%v of %v inputs invalid", invalid, len(inputs))
+	}
+	if *check && changed > 0 {
+		return fmt.Errorf("%v of %v inputs not canonical", changed, len(inputs))
+	}
+	return nil
+}
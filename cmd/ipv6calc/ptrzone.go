@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//ptrRecord is one line of a mapping file
+type ptrRecord struct {
+	addr *ipv6calc.Addr
+	host string
+	line int
+}
+
+//checkHostname validates name as DNS host name and returns it fully
+//qualified, names in mapping files are always absolute
+func checkHostname(name string) (string, error) {
+	fqdn := strings.TrimSuffix(name, ".")
+	if len(fqdn) == 0 || len(fqdn) > 253 {
+		return "", fmt.Errorf("invalid host name length %q", name)
+	}
+	for _, l := range strings.Split(fqdn, ".") {
+		if len(l) == 0 || len(l) > 63 || l[0] == '-' || l[len(l)-1] == '-' {
+			return "", fmt.Errorf("invalid label %q in host name %q", l, name)
+		}
+		for i := 0; i < len(l); i++ {
+			c := l[i]
+			if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return "", fmt.Errorf("invalid character %q in host name %q", c, name)
+			}
+		}
+	}
+	return fqdn + ".", nil
+}
+
+//readMapping reads "address hostname" lines of addresses inside p, empty
+//lines and # comments are skipped
+func readMapping(name string, p *ipv6calc.Prefix) ([]ptrRecord, error) {
+	ret := make([]ptrRecord, 0)
+	seen := make(map[ipv6calc.Addr]int)
+	err := scanLines(name, func(n int, line string) error {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return errors.New("expected address and host name")
+		}
+		a, err := parseAddr(fields[0])
+		if err != nil {
+			return err
+		}
+		if !p.Contains(a) {
+			return fmt.Errorf("%v is not in %v", fields[0], fmtPrefix(p))
+		}
+		key := ipv6calc.AddrFromUint64(a.High(), a.Low())
+		if prev, ok := seen[key]; ok {
+			return fmt.Errorf("%v already mapped on line %v", fields[0], prev)
+		}
+		seen[key] = n
+		host, err := checkHostname(fields[1])
+		if err != nil {
+			return err
+		}
+		ret = append(ret, ptrRecord{a, host, n})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+//writeZone writes a BIND zone file with SOA and NS records and PTR records
+//named relative to the zone origin
+func writeZone(w *bufio.Writer, zone string, records []ptrRecord, ns []string, mbox string, serial string, ttl uint) {
+	fmt.Fprintf(w, "$ORIGIN %v\n", zone)
+	fmt.Fprintf(w, "$TTL %v\n", ttl)
+	fmt.Fprintf(w, "@\tIN\tSOA\t%v %v (\n", ns[0], mbox)
+	fmt.Fprintf(w, "\t\t\t%v\t; serial\n", serial)
+	fmt.Fprintf(w, "\t\t\t3600\t\t; refresh\n")
+	fmt.Fprintf(w, "\t\t\t900\t\t; retry\n")
+	fmt.Fprintf(w, "\t\t\t1209600\t\t; expire\n")
+	fmt.Fprintf(w, "\t\t\t%v\t\t; minimum\n", ttl)
+	fmt.Fprintf(w, "\t\t\t)\n")
+	for _, n := range ns {
+		fmt.Fprintf(w, "@\tIN\tNS\t%v\n", n)
+	}
+	fmt.Fprintln(w)
+	for _, r := range records {
+		owner := strings.TrimSuffix(r.addr.ReverseName(), "."+zone)
+		fmt.Fprintf(w, "%v\tIN\tPTR\t%v\n", owner, r.host)
+	}
+}
+
+func runPTRZone(fs *flag.FlagSet, args []string) error {
+	nsList := fs.String("ns", "ns1.example.com.,ns2.example.com.", "comma separated name servers, the first one goes to SOA")
+	mbox := fs.String("mbox", "hostmaster.example.com.", "SOA mailbox of the zone administrator")
+	serial := fs.String("serial", time.Now().UTC().Format("20060102")+"00", "SOA serial")
+	ttl := fs.Uint("ttl", 3600, "default TTL")
+	dir := fs.String("dir", "", "write each zone into <zone>zone file in this directory instead of stdout, required when the prefix needs several zones")
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	zones := p.ReverseZones()
+	if len(zones) > 1 && len(*dir) == 0 {
+		return usageErrorf("/%v is not on a nibble boundary, its %v zones need -dir", p.Mask(), len(zones))
+	}
+	ns := make([]string, 0)
+	for _, n := range strings.Split(*nsList, ",") {
+		h, err := checkHostname(strings.TrimSpace(n))
+		if err != nil {
+			return err
+		}
+		ns = append(ns, h)
+	}
+	m, err := checkHostname(*mbox)
+	if err != nil {
+		return err
+	}
+	records, err := readMapping(args[1], p)
+	if err != nil {
+		return err
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].addr.Cmp(records[j].addr) < 0
+	})
+	zoneMask := (p.Mask() + 3) / 4 * 4
+	byZone := make(map[string][]ptrRecord)
+	for _, r := range records {
+		sup, err := ipv6calc.NewPrefix(*r.addr, 128)
+		if err != nil {
+			return err
+		}
+		zp, err := sup.Supernet(zoneMask)
+		if err != nil {
+			return err
+		}
+		name, _ := zp.ReverseZone()
+		byZone[name] = append(byZone[name], r)
+	}
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+	if len(*dir) == 0 {
+		writeZone(out, zones[0], byZone[zones[0]], ns, m, *serial, *ttl)
+		return nil
+	}
+	for _, zone := range zones {
+		f, err := os.Create(filepath.Join(*dir, zone+"zone"))
+		if err != nil {
+			return err
+		}
+		w := bufio.NewWriter(f)
+		writeZone(w, zone, byZone[zone], ns, m, *serial, *ttl)
+		if err := w.Flush(); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+		fmt.Fprintln(out, f.Name())
+	}
+	return nil
+}
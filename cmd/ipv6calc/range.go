@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+func runCidr(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 1, 2)
+	if err != nil {
+		return err
+	}
+	r, err := ipv6calc.ParseRange(strings.Join(args, "-"))
+	if err != nil {
+		return err
+	}
+	for _, p := range r.Prefixes() {
+		fmt.Println(fmtPrefix(p))
+	}
+	return nil
+}
+
+func runRange(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	r := p.Range()
+	first, last := r.First(), r.Last()
+	fmt.Printf("%v-%v\n", fmtAddr(&first), fmtAddr(&last))
+	return nil
+}
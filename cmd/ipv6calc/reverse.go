@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//reverse turns an address into its PTR name, a prefix into its ip6.arpa
+//zones and an ip6.arpa name back into an address or prefix
+func reverse(s string) ([]string, error) {
+	if strings.HasSuffix(strings.ToLower(strings.TrimSuffix(s, ".")), "arpa") {
+		p, err := ipv6calc.ParseReverseName(s)
+		if err != nil {
+			return nil, err
+		}
+		if p.Mask() == 128 {
+			a := p.Addr()
+			return []string{fmtAddr(&a)}, nil
+		}
+		return []string{fmtPrefix(p)}, nil
+	}
+	p, err := parsePrefix(s)
+	if err != nil {
+		return nil, err
+	}
+	if p.Mask() == 128 {
+		a := p.Addr()
+		return []string{a.ReverseName()}, nil
+	}
+	return p.ReverseZones(), nil
+}
+
+func runReverse(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 0, -1)
+	if err != nil {
+		return err
+	}
+	inputs, err := readInputs(args)
+	if err != nil {
+		return err
+	}
+	invalid := 0
+	for _, in := range inputs {
+		out, err := reverse(in)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ipv6calc reverse: %v\n", err)
+			invalid++
+			continue
+		}
+		for _, o := range out {
+			fmt.Println(o)
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%v of %v inputs invalid", invalid, len(inputs))
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+//readSet reads prefixes and first-last ranges, one per line, from file or
+//stdin for "-"; empty lines and # comments are skipped
+func readSet(name string) (*ipv6calc.Set, error) {
+	ps := make([]*ipv6calc.Prefix, 0)
+	rs := make([]*ipv6calc.Range, 0)
+	err := scanLines(name, func(n int, line string) error {
+		if strings.Contains(line, "-") {
+			rg, err := ipv6calc.ParseRange(line)
+			if err != nil {
+				return err
+			}
+			rs = append(rs, rg)
+			return nil
+		}
+		p, err := parsePrefix(line)
+		if err != nil {
+			return err
+		}
+		ps = append(ps, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return ipv6calc.SetFromPrefixes(ps...).Union(ipv6calc.SetFromRanges(rs...)), nil
+}
+
+func runSet(fs *flag.FlagSet, args []string) error {
+	size := fs.Bool("size", false, "print number of addresses instead of prefixes")
+	args, err := parseArgs(fs, args, 3, 3)
+	if err != nil {
+		return err
+	}
+	var op func(a, b *ipv6calc.Set) *ipv6calc.Set
+	switch args[0] {
+	case "union":
+		op = (*ipv6calc.Set).Union
+	case "intersect":
+		op = (*ipv6calc.Set).Intersection
+	case "diff":
+		op = (*ipv6calc.Set).Difference
+	default:
+		return usageErrorf("unknown set operation %q", args[0])
+	}
+	if args[1] == "-" && args[2] == "-" {
+		return usageErrorf("only one file can be read from stdin")
+	}
+	a, err := readSet(args[1])
+	if err != nil {
+		return err
+	}
+	b, err := readSet(args[2])
+	if err != nil {
+		return err
+	}
+	r := op(a, b)
+	if *size {
+		fmt.Println(r.Size())
+		return nil
+	}
+	for _, p := range r.Prefixes() {
+		fmt.Println(fmtPrefix(p))
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+)
+
+func runSolicited(fs *flag.FlagSet, args []string) error {
+	in := fs.String("in", "", "list addresses of this prefix mapping to the given solicited-node address")
+	first := fs.Uint64("first", 0, "with -in print only this many addresses")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	a, err := parseAddr(args[0])
+	if err != nil {
+		return err
+	}
+	if len(*in) == 0 {
+		if isFlagSet(fs, "first") {
+			return usageErrorf("-first needs -in")
+		}
+		fmt.Println(fmtAddr(a.SolicitedNode()))
+		return nil
+	}
+	p, err := parsePrefix(*in)
+	if err != nil {
+		return err
+	}
+	it, err := p.SolicitedNodeMembers(a)
+	if err != nil {
+		return err
+	}
+	for n := uint64(0); !isFlagSet(fs, "first") || n < *first; n++ {
+		m := it.Next()
+		if m == nil {
+			break
+		}
+		fmt.Println(fmtAddr(m))
+	}
+	return nil
+}
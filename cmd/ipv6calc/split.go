@@ -0,0 +1,94 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//isFlagSet tells if flag was given on the command line
+func isFlagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func parseMask(s string) (uint, error) {
+	mask, err := strconv.ParseUint(strings.TrimPrefix(s, "/"), 10, 8)
+	if err != nil || mask > 128 {
+		return 0, fmt.Errorf("invalid prefix length %q", s)
+	}
+	return uint(mask), nil
+}
+
+func runSplit(fs *flag.FlagSet, args []string) error {
+	first := fs.Uint64("first", 0, "print only this many children")
+	every := fs.Uint64("every", 1, "print every k-th child, starting with the first")
+	nth := fs.Uint64("nth", 0, "print only child number n, counting from 0")
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	if isFlagSet(fs, "nth") && (isFlagSet(fs, "first") || isFlagSet(fs, "every")) {
+		return usageErrorf("-nth cannot be combined with -first or -every")
+	}
+	if *every == 0 {
+		return usageErrorf("-every must be at least 1")
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	mask, err := parseMask(args[1])
+	if err != nil {
+		return err
+	}
+
+	if isFlagSet(fs, "nth") {
+		c, err := p.Child(mask, *nth)
+		if err != nil {
+			return err
+		}
+		fmt.Println(fmtPrefix(c))
+		return nil
+	}
+	it, err := p.Split(mask)
+	if err != nil {
+		return err
+	}
+	for n := uint64(0); !isFlagSet(fs, "first") || n < *first; n++ {
+		c := it.Next()
+		if c == nil {
+			break
+		}
+		fmt.Println(fmtPrefix(c))
+		it.Skip(*every - 1)
+	}
+	return nil
+}
+
+func runCount(fs *flag.FlagSet, args []string) error {
+	args, err := parseArgs(fs, args, 2, 2)
+	if err != nil {
+		return err
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	mask, err := parseMask(args[1])
+	if err != nil {
+		return err
+	}
+	n, err := p.NumSubnets(mask)
+	if err != nil {
+		return err
+	}
+	fmt.Println(n.Human())
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net"
+
+	"github.com/helotpl/ipv6calc"
+)
+
+func runStable(fs *flag.FlagSet, args []string) error {
+	secret := fs.String("secret", "", "secret key, with -linux the stable_secret address")
+	iface := fs.String("iface", "", "interface name")
+	network := fs.String("network", "", "network ID, like SSID")
+	dad := fs.Uint("dad", 0, "DAD counter")
+	linux := fs.Bool("linux", false, "compute as Linux addr_gen_mode=stable_privacy does, from -mac instead of -iface and -network")
+	mac := fs.String("mac", "", "with -linux permanent MAC address of the interface, zeros when not given")
+	args, err := parseArgs(fs, args, 1, 1)
+	if err != nil {
+		return err
+	}
+	if len(*secret) == 0 {
+		return usageErrorf("-secret is required")
+	}
+	if *dad > 255 {
+		return usageErrorf("-dad must be at most 255")
+	}
+	if *linux && (isFlagSet(fs, "iface") || isFlagSet(fs, "network")) {
+		return usageErrorf("-linux uses -mac, not -iface and -network")
+	}
+	if !*linux && isFlagSet(fs, "mac") {
+		return usageErrorf("-mac needs -linux")
+	}
+	p, err := parsePrefix(args[0])
+	if err != nil {
+		return err
+	}
+	var a *ipv6calc.Addr
+	if *linux {
+		s, err := parseAddr(*secret)
+		if err != nil {
+			return err
+		}
+		hw := make(net.HardwareAddr, 6)
+		if len(*mac) > 0 {
+			if hw, err = ipv6calc.ParseMAC(*mac); err != nil {
+				return err
+			}
+		}
+		a, err = p.LinuxStablePrivacy(hw, uint8(*dad), s)
+		if err != nil {
+			return err
+		}
+	} else {
+		a, err = p.StablePrivacy([]byte(*iface), []byte(*network), uint8(*dad), []byte(*secret))
+		if err != nil {
+			return err
+		}
+	}
+	fmt.Println(fmtAddr(a))
+	return nil
+}
@@ -0,0 +1,32 @@
+package ipv6calc
+
+//sameBits compares addresses ignoring zones
+func (i6 *Addr) sameBits(i *Addr) bool {
+	return i6.high == i.high && i6.low == i.low
+}
+
+//Contains tells if address belongs to the prefix, zones are not compared
+func (p *Prefix) Contains(a *Addr) bool {
+	return a.And(p.AddrMask()).sameBits(p.FirstAddressFromSubnet())
+}
+
+//ContainsPrefix tells if every address of q belongs to p
+func (p *Prefix) ContainsPrefix(q *Prefix) bool {
+	return q.mask >= p.mask && p.Contains(&q.addr)
+}
+
+//Overlaps tells if p and q have at least one common address, for prefixes it
+//means that one contains the other
+func (p *Prefix) Overlaps(q *Prefix) bool {
+	return p.ContainsPrefix(q) || q.ContainsPrefix(p)
+}
+
+//IsSiblingOf tells if p and q are two different halves of the same parent
+//prefix, so together they form a prefix one bit shorter
+func (p *Prefix) IsSiblingOf(q *Prefix) bool {
+	if p.mask != q.mask || p.mask == 0 {
+		return false
+	}
+	parent := Prefix{p.addr, p.mask - 1, nil}
+	return parent.Contains(&q.addr) && !p.Contains(&q.addr)
+}
@@ -0,0 +1,69 @@
+package ipv6calc
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+)
+
+//ParseMAC parses a 48 bit MAC address written with colons
+//(00:11:22:33:44:55), dashes (00-11-22-33-44-55), Cisco dots
+//(0011.2233.4455) or as 12 hex digits
+func ParseMAC(s string) (net.HardwareAddr, error) {
+	if len(s) == 12 {
+		b, err := hex.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid MAC address %q", s)
+		}
+		return net.HardwareAddr(b), nil
+	}
+	mac, err := net.ParseMAC(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("%q is not a 48 bit MAC address", s)
+	}
+	return mac, nil
+}
+
+//EUI64InterfaceID returns modified EUI-64 interface ID of a MAC address,
+//ff:fe is inserted in the middle and universal/local bit is flipped as
+//defined by RFC 4291 appendix A
+func EUI64InterfaceID(mac net.HardwareAddr) (uint64, error) {
+	if len(mac) != 6 {
+		return 0, fmt.Errorf("MAC address must have 6 bytes, not %v", len(mac))
+	}
+	id := uint64(mac[0]^0x02)<<56 | uint64(mac[1])<<48 | uint64(mac[2])<<40 |
+		0xfffe<<24 |
+		uint64(mac[3])<<16 | uint64(mac[4])<<8 | uint64(mac[5])
+	return id, nil
+}
+
+//EUI64 returns SLAAC address of a MAC address in a /64 prefix
+func (p *Prefix) EUI64(mac net.HardwareAddr) (*Addr, error) {
+	if p.mask != 64 {
+		return nil, fmt.Errorf("EUI-64 addresses need a /64 prefix, not /%v", p.mask)
+	}
+	id, err := EUI64InterfaceID(mac)
+	if err != nil {
+		return nil, err
+	}
+	return &Addr{p.addr.high, id, p.addr.zone}, nil
+}
+
+//IsEUI64 checks if the interface ID (last 64 bits) has ff:fe in the middle
+//as modified EUI-64 IDs built from MAC addresses do
+func (i6 *Addr) IsEUI64() bool {
+	return (i6.low>>24)&0xffff == 0xfffe
+}
+
+//MAC recovers MAC address from a modified EUI-64 interface ID, ok is false
+//when the interface ID is not one
+func (i6 *Addr) MAC() (mac net.HardwareAddr, ok bool) {
+	if !i6.IsEUI64() {
+		return nil, false
+	}
+	l := i6.low
+	return net.HardwareAddr{byte(l>>56) ^ 0x02, byte(l >> 48), byte(l >> 40), byte(l >> 16), byte(l >> 8), byte(l)}, true
+}
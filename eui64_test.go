@@ -0,0 +1,63 @@
+package ipv6calc
+
+import "testing"
+
+func TestParseMAC(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"00:11:22:33:44:55", "00:11:22:33:44:55"},
+		{"00-11-22-33-44-55", "00:11:22:33:44:55"},
+		{"0011.2233.4455", "00:11:22:33:44:55"},
+		{"001122334455", "00:11:22:33:44:55"},
+		{"AABBCCDDEEFF", "aa:bb:cc:dd:ee:ff"},
+		{"00112233445g", ""},
+		{"00:11:22:33:44:55:66:77", ""},
+		{"00:11:22:33:44", ""},
+	}
+	for _, tt := range tests {
+		mac, err := ParseMAC(tt.in)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("ParseMAC(%q) = %v, want error", tt.in, mac)
+			}
+			continue
+		}
+		if err != nil || mac.String() != tt.want {
+			t.Errorf("ParseMAC(%q) = %v, %v, want %v", tt.in, mac, err, tt.want)
+		}
+	}
+}
+
+func TestEUI64(t *testing.T) {
+	tests := []struct {
+		mac  string
+		addr string
+	}{
+		{"00:11:22:33:44:55", "2001:db8::211:22ff:fe33:4455"},
+		//universal/local bit is flipped back
+		{"02:00:00:00:00:01", "2001:db8::ff:fe00:1"},
+		{"ff:ff:ff:ff:ff:ff", "2001:db8::fdff:ffff:feff:ffff"},
+	}
+	p := mustPrefix(t, "2001:db8::/64")
+	for _, tt := range tests {
+		mac, _ := ParseMAC(tt.mac)
+		a, err := p.EUI64(mac)
+		if err != nil || a.CanonicalString() != tt.addr {
+			t.Errorf("EUI64(%v) = %v, %v, want %v", tt.mac, a, err, tt.addr)
+			continue
+		}
+		back, ok := a.MAC()
+		if !a.IsEUI64() || !ok || back.String() != tt.mac {
+			t.Errorf("MAC() of %v = %v, %v, want %v", tt.addr, back, ok, tt.mac)
+		}
+	}
+	if mac, ok := mustAddr(t, "2001:db8::1").MAC(); ok {
+		t.Errorf("MAC() of 2001:db8::1 = %v, want not EUI-64", mac)
+	}
+	mac, _ := ParseMAC("00:11:22:33:44:55")
+	if _, err := mustPrefix(t, "2001:db8::/48").EUI64(mac); err == nil {
+		t.Errorf("EUI64 in a /48 succeeded")
+	}
+}
@@ -0,0 +1,32 @@
+package ipv6calc
+
+//Exclude returns addresses of p without addresses of qs as the minimal list
+//of prefixes, in order. Excluded prefixes do not have to lie inside p.
+func (p *Prefix) Exclude(qs ...*Prefix) []*Prefix {
+	ret := make([]*Prefix, 0)
+	cur := p.FirstAddressFromSubnet()
+	last := p.LastAddressFromSubnet()
+	//aggregated list is sorted and has no overlapping prefixes
+	for _, q := range Aggregate(qs) {
+		if !p.Overlaps(q) {
+			continue
+		}
+		if q.ContainsPrefix(p) {
+			return ret
+		}
+		qFirst := q.FirstAddressFromSubnet()
+		if cur.less(qFirst) {
+			r := Range{*cur, *qFirst.Dec()}
+			ret = append(ret, r.Prefixes()...)
+		}
+		cur = q.LastAddressFromSubnet().Inc()
+		if cur == nil {
+			return ret
+		}
+	}
+	if !last.less(cur) {
+		r := Range{*cur, *last}
+		ret = append(ret, r.Prefixes()...)
+	}
+	return ret
+}
@@ -0,0 +1,38 @@
+package ipv6calc
+
+import "testing"
+
+func TestExclude(t *testing.T) {
+	tests := []struct {
+		p    string
+		qs   []string
+		want []string
+	}{
+		{"2001:db8::/32", []string{"2001:db8::/34"}, []string{"2001:db8:4000::/34", "2001:db8:8000::/33"}},
+		{"2001:db8::/32", []string{"2001:db8:4000::/34"}, []string{"2001:db8::/34", "2001:db8:8000::/33"}},
+		{"2001:db8::/46", []string{"2001:db8:1::/48", "2001:db8:3::/48"}, []string{"2001:db8::/48", "2001:db8:2::/48"}},
+		//excluded prefixes outside p are ignored
+		{"2001:db8::/32", []string{"2001:db9::/32"}, []string{"2001:db8::/32"}},
+		{"2001:db8::/32", []string{"2001::/16"}, []string{}},
+		{"2001:db8::/32", []string{"2001:db8::/32"}, []string{}},
+		{"2001:db8::/126", []string{"2001:db8::/128", "2001:db8::3/128"}, []string{"2001:db8::1/128", "2001:db8::2/128"}},
+		//excluded prefix reaching the end of address space
+		{"ffff::/16", []string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe/127"}, nil},
+		{"::/0", []string{"::/1"}, []string{"8000::/1"}},
+	}
+	for _, tt := range tests {
+		got := mustPrefix(t, tt.p).Exclude(mustPrefixes(t, tt.qs...)...)
+		if tt.want == nil {
+			//check by counting: everything but the last two addresses
+			s := SetFromPrefixes(got...)
+			want := SetFromPrefixes(mustPrefix(t, tt.p)).Difference(SetFromPrefixes(mustPrefixes(t, tt.qs...)...))
+			if s.Size().Cmp(want.Size()) != 0 || len(got) != 111 || s.Contains(mustAddr(t, "ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe")) {
+				t.Errorf("Exclude(%v, %v) returned %v prefixes of %v addresses", tt.p, tt.qs, len(got), s.Size())
+			}
+			continue
+		}
+		if got := prefixStrings(got); !equalStrings(got, tt.want) {
+			t.Errorf("Exclude(%v, %v) = %v, want %v", tt.p, tt.qs, got, tt.want)
+		}
+	}
+}
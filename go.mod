@@ -0,0 +1,3 @@
+module github.com/helotpl/ipv6calc
+
+go 1.18
@@ -0,0 +1,23 @@
+package ipv6calc
+
+import (
+	"fmt"
+	"strings"
+)
+
+//IPv4 returns low 32 bits of the address in dotted notation
+func (i6 *Addr) IPv4() string {
+	return fmt.Sprintf("%d.%d.%d.%d", byte(i6.low>>24), byte(i6.low>>16), byte(i6.low>>8), byte(i6.low))
+}
+
+//EmbeddedIPv4String formats address with low 32 bits in dotted notation,
+//as used by v4-mapped (::ffff:192.0.2.1) and NAT64 (64:ff9b::198.51.100.7)
+//addresses
+func (i6 *Addr) EmbeddedIPv4String() string {
+	s := i6.StringTokens(false)[:6]
+	z := findBestZeros(findZerosInTokens(s))
+	if z.count() == 0 {
+		return strings.Join(append(s, i6.IPv4()), ":") + i6.zoneSuffix()
+	}
+	return strings.Join(s[:z.start], ":") + "::" + strings.Join(append(s[z.stop:], i6.IPv4()), ":") + i6.zoneSuffix()
+}
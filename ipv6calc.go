@@ -1,4 +1,6 @@
-package main
+//Package ipv6calc implements IPv6 address and prefix arithmetic: parsing,
+//formatting, masking and walking subnets.
+package ipv6calc
 
 import (
 	"errors"
@@ -9,36 +11,64 @@ import (
 	"strings"
 )
 
-type ipv6token [4]byte
-
-type ipv6tokenized []ipv6token
-
-type ipv6addr struct {
+type Addr struct {
 	high uint64
 	low  uint64
+	zone string
 }
 
-type ipv6prefix struct {
-	addr     ipv6addr
+type Prefix struct {
+	addr     Addr
 	mask     uint
-	addrMask *ipv6addr
+	addrMask *Addr
+}
+
+//ExposeChar marks a single hex digit of an address with a character
+//placed before or after it, position counts hex digits from the left
+type ExposeChar struct {
+	Before   bool //if not before than after
+	Position uint
+	Char     rune
 }
 
-type exposeChar struct {
-	before   bool //if not before than after
-	position uint
-	char     rune
+//ExposeRange marks bits StartBit...EndBit (counted from the left, both
+//included) with StartChar before and EndChar after the hex digits holding them
+type ExposeRange struct {
+	StartBit  uint
+	EndBit    uint
+	StartChar rune
+	EndChar   rune
 }
 
-type hexPrintConfig struct {
-	upcase          bool
-	leasingZeros    bool
-	exposeStartChar rune
-	exposeEndChar   rune
+//HexPrintConfig controls how formatters print hex groups: Upcase prints
+//A-F, LeadingZeros pads every group to 4 digits and expose chars mark
+//bounds of the exposed digits
+type HexPrintConfig struct {
+	Upcase          bool
+	LeadingZeros    bool
+	ExposeStartChar rune
+	ExposeEndChar   rune
 }
 
-func makeDefaultHexPrintConfig() hexPrintConfig {
-	return hexPrintConfig{false, false, leftExposeRune, rightExposeRune}
+//DefaultHexPrintConfig returns lowercase config without padding and with
+//<> expose markers
+func DefaultHexPrintConfig() HexPrintConfig {
+	return HexPrintConfig{false, false, leftExposeRune, rightExposeRune}
+}
+
+//hexFormat returns Sprintf format printing at least width digits
+func (c *HexPrintConfig) hexFormat(width uint) string {
+	if c.LeadingZeros {
+		width = 4
+	}
+	verb := "x"
+	if c.Upcase {
+		verb = "X"
+	}
+	if width > 0 {
+		return fmt.Sprintf("%%0%v%v", width, verb)
+	}
+	return "%" + verb
 }
 
 const leftExposeChar = '<'
@@ -59,149 +89,6 @@ func checkHexChar(b byte) bool {
 	return false
 }
 
-func cleanToken() *ipv6token {
-	t := new(ipv6token)
-	copy(t[:], "0000"[:])
-	return t
-}
-
-func (t *ipv6token) pushHexChar(b byte) error {
-	if checkHexChar(b) {
-		copy(t[0:3], t[1:4])
-		t[3] = b
-	} else {
-		return errors.New("not a hex char")
-	}
-	return nil
-}
-
-func (t *ipv6token) fillHexChar(b byte) error {
-	if checkHexChar(b) {
-
-	} else {
-		return errors.New("not a hex char")
-	}
-	return nil
-}
-
-func (t *ipv6token) String() string {
-	return string(t[:])
-}
-
-func (t ipv6tokenized) String() string {
-	s := make([]string, len(t))
-	for i := range t {
-		s[i] = t[i].String()
-	}
-	return strings.Join(s, ":")
-}
-
-func tokenFromString(s string) *ipv6token {
-	t := cleanToken()
-	r := []rune(s)
-
-	curr := 3
-	//reverse iterate over runes
-	for i := len(r) - 1; i >= 0; i-- {
-		rune := r[i]
-		//skip unicode characters
-		if rune < 256 {
-			b := byte(rune)
-			if checkHexChar(b) {
-				t[curr] = b
-				curr--
-				if curr < 0 {
-					break
-				}
-			}
-		}
-	}
-	return t
-}
-
-//ff = 8bit
-//ffff = 16bit
-//128/16 = 8 tokens
-//function accepts only bare IPv6 address
-func tokenizeIPv6(s string) (ret []string, e error) {
-	parts := strings.Split(s, ":")
-
-	if len(parts) > 8 {
-		return parts, errors.New("too many colons in ipv6 address")
-	}
-
-	empty, err := findEmptyToken(parts)
-	if err != nil {
-		return parts, err
-	}
-
-	if empty > 0 {
-		parts[empty] = "0"
-	}
-	missing := 8 - len(parts)
-	if missing > 0 {
-		if empty == -1 {
-			return parts, errors.New("not enough colons in ipv6 address without double colon")
-		}
-		//we can fill up missing tokens
-		fill := zeroTokens(missing)
-		parts2 := make([]string, 8)
-		copy(parts2[:empty], parts[:empty])
-		copy(parts2[empty:empty+missing], fill[:missing])
-		copy(parts2[empty+missing:], parts[empty:])
-		parts = parts2
-	}
-
-	return parts, nil
-}
-
-func makeTokens(s []string) ipv6tokenized {
-	ret := make(ipv6tokenized, len(s))
-	for i, v := range s {
-		ret[i] = *tokenFromString(v)
-	}
-	return ret
-}
-
-func findEmptyToken(ss []string) (num int, e error) {
-	empty := -1
-	maxssind := len(ss)
-	for i := 1; i < maxssind-1; i++ {
-		if len(ss[i]) == 0 {
-			if empty < 0 {
-				empty = i
-			} else {
-				return -1, errors.New("illegal double empty tokens")
-			}
-		}
-	}
-	//this checks are unnessesary, because these situations are NOT AMBIGOUS
-	//but designers of IPv6 addressing apparently know better
-	if len(ss[0]) == 0 && empty != 1 {
-		return -1, errors.New("illegal empty token at the start")
-	}
-	if len(ss[maxssind-1]) == 0 && empty != maxssind-2 {
-		return -1, errors.New("illegal empty token at the end")
-	}
-	return empty, nil
-}
-
-func zeroTokens(num int) []string {
-	ret := make([]string, num)
-	for i := range ret {
-		ret[i] = "0"
-	}
-	return ret
-}
-
-func tokensToByteString(t []ipv6token) []byte {
-	ret := make([]byte, len(t)*4)
-	for i := range t {
-		copy(ret[i*4:], t[i][:])
-	}
-	return ret
-}
-
 func hexToInt(b byte) uint64 {
 	if b >= '0' && b <= '9' {
 		return uint64(b) - '0'
@@ -215,30 +102,7 @@ func hexToInt(b byte) uint64 {
 	return 0
 }
 
-func mergeTokens(t []ipv6token) []byte {
-	ret := make([]byte, len(t)*4)
-	for i := range t {
-		copy(ret[i*4:], t[i][:])
-	}
-	return ret
-}
-
-// 1 hex == 4 bits
-// 16 hex == 64 bits
-func hexStringToInt(b []byte) (r uint64, e error) {
-	if len(b) > 16 {
-		return 0, errors.New("hex string too long to fit into int64")
-	}
-	var ret uint64 = 0
-
-	for _, v := range b {
-		ret = ret<<4 + hexToInt(v)
-	}
-
-	return ret, nil
-}
-
-func (i6 *ipv6addr) asHex() string {
+func (i6 *Addr) Hex() string {
 	ret := fmt.Sprintf("%016x%016x", i6.high, i6.low)
 	return ret
 }
@@ -266,12 +130,9 @@ func retokenize(s string) string {
 	return string(o)
 }
 
-func toHexToken(num uint64, token int, leadingZeros bool) string {
+func toHexToken(num uint64, token int, c HexPrintConfig) string {
 	num = (num >> (token * 16)) & 0xFFFF
-	if leadingZeros {
-		return fmt.Sprintf("%04x", num)
-	}
-	return fmt.Sprintf("%x", num)
+	return fmt.Sprintf(c.hexFormat(0), num)
 }
 
 func (e *exposeInToken) minZerosFromExpose() uint {
@@ -284,68 +145,59 @@ func (e *exposeInToken) minZerosFromExpose() uint {
 	return 4 - e.start
 }
 
-func toHexTokenExpose(num uint64, token int, localExp exposeInToken) string {
+func toHexTokenExpose(num uint64, token int, localExp exposeInToken, c HexPrintConfig) string {
 	num = (num >> (token * 16)) & 0xFFFF
-	minZeros := localExp.minZerosFromExpose()
-	var format string
-	if minZeros > 0 {
-		format = fmt.Sprintf("%%0%vx", minZeros)
-	} else {
-		format = "%x"
-	}
-	ret := fmt.Sprintf(format, num)
-	//add := uint(0)
+	ret := fmt.Sprintf(c.hexFormat(localExp.minZerosFromExpose()), num)
 	if !localExp.empty {
-		if !localExp.contLeft {
-			space := localExp.start
-			//if !(token == 0 && space == 0) {
-			space += uint(len(ret)) - 4
-			ret = ret[:space] + string(leftExposeChar) + ret[space:]
-			//add++
-			//}
-		}
+		digits := uint(len(ret))
+		//end char first, so start char does not move its position
 		if !localExp.contRight {
-			space := localExp.stop
-			//if !(token == 7 && space == 4) {
-			space += uint(len(ret)) - 4 + 1
-			ret = ret[:space] + string(rightExposeChar) + ret[space:]
-			//}
+			space := localExp.stop + digits - 4 + 1
+			ret = ret[:space] + string(c.ExposeEndChar) + ret[space:]
+		}
+		if !localExp.contLeft {
+			space := localExp.start + digits - 4
+			ret = ret[:space] + string(c.ExposeStartChar) + ret[space:]
 		}
 	}
 	return ret
 }
 
-func toHexTokenMultiExpose(num uint64, token int, localExposes []exposeChar) string {
+//toHexTokenMultiExpose expects positions local to the token (0...3)
+func toHexTokenMultiExpose(num uint64, token int, localExposes []ExposeChar, minZeros uint, c HexPrintConfig) string {
 	num = (num >> (token * 16)) & 0xFFFF
-	minZeros := uint(0)
 	for i := range localExposes {
-		nminZeros := 4 - localExposes[i].position
+		nminZeros := 4 - localExposes[i].Position
 		if nminZeros > minZeros {
 			minZeros = nminZeros
 		}
 	}
-	var format string
-	if minZeros > 0 {
-		format = fmt.Sprintf("%%0%vx", minZeros)
-	} else {
-		format = "%v"
-	}
-	ret := fmt.Sprintf(format, num)
-	for _, v := range localExposes {
-		pos := uint(len(ret)) - 4 + v.position
-		if v.before == false {
-			pos++
+	digits := fmt.Sprintf(c.hexFormat(minZeros), num)
+	//first printed digit has local position 4-len(digits)
+	skip := uint(4 - len(digits))
+	var b strings.Builder
+	for i := range digits {
+		pos := uint(i) + skip
+		for _, v := range localExposes {
+			if v.Before && v.Position == pos {
+				b.WriteRune(v.Char)
+			}
+		}
+		b.WriteByte(digits[i])
+		for _, v := range localExposes {
+			if !v.Before && v.Position == pos {
+				b.WriteRune(v.Char)
+			}
 		}
-		ret = ret[:pos] + string(v.char) + ret[pos:]
 	}
-	return ret
+	return b.String()
 }
 
-func (i6 *ipv6addr) asHexToken(token int, leadingZeros bool) string {
+func (i6 *Addr) asHexToken(token int, c HexPrintConfig) string {
 	if token > 3 {
-		return toHexToken(i6.high, token-4, leadingZeros)
+		return toHexToken(i6.high, token-4, c)
 	}
-	return toHexToken(i6.low, token, leadingZeros)
+	return toHexToken(i6.low, token, c)
 }
 
 func (e exposeInToken) localExpose(token int) *exposeInToken {
@@ -357,14 +209,14 @@ func (e exposeInToken) localExpose(token int) *exposeInToken {
 	return &e
 }
 
-func (i6 *ipv6addr) asHexTokenExpose(token int, e exposeInToken) string {
+func (i6 *Addr) asHexTokenExpose(token int, e exposeInToken, c HexPrintConfig) string {
 	if token > 3 {
-		return toHexTokenExpose(i6.high, token-4, e)
+		return toHexTokenExpose(i6.high, token-4, e, c)
 	}
-	return toHexTokenExpose(i6.low, token, e)
+	return toHexTokenExpose(i6.low, token, e, c)
 }
 
-func (i6 *ipv6addr) asBigInt() *big.Int {
+func (i6 *Addr) BigInt() *big.Int {
 	var h, l, ret big.Int
 
 	h.SetUint64(i6.high)
@@ -375,33 +227,33 @@ func (i6 *ipv6addr) asBigInt() *big.Int {
 	return &ret
 }
 
-func (i6 *ipv6addr) And(i *ipv6addr) *ipv6addr {
+func (i6 *Addr) And(i *Addr) *Addr {
 	nh := i6.high & i.high
 	nl := i6.low & i.low
-	return &ipv6addr{nh, nl}
+	return &Addr{nh, nl, i6.zone}
 }
 
-func (i6 *ipv6addr) Or(i *ipv6addr) *ipv6addr {
+func (i6 *Addr) Or(i *Addr) *Addr {
 	nh := i6.high | i.high
 	nl := i6.low | i.low
-	return &ipv6addr{nh, nl}
+	return &Addr{nh, nl, i6.zone}
 }
 
-func (i6 *ipv6addr) Neg() *ipv6addr {
-	return &ipv6addr{^i6.high, ^i6.low}
+func (i6 *Addr) Neg() *Addr {
+	return &Addr{^i6.high, ^i6.low, i6.zone}
 }
 
-func (i6 *ipv6addr) Xor(i *ipv6addr) *ipv6addr {
+func (i6 *Addr) Xor(i *Addr) *Addr {
 	nh := i6.high ^ i.high
 	nl := i6.low ^ i.low
-	return &ipv6addr{nh, nl}
+	return &Addr{nh, nl, i6.zone}
 }
 
-func (i6 *ipv6addr) CummulativeXor(i1, i2 *ipv6addr) *ipv6addr {
+func (i6 *Addr) CummulativeXor(i1, i2 *Addr) *Addr {
 	return i6.Or(i1.Xor(i2))
 }
 
-func (i6 *ipv6addr) BitsRange() (start, stop uint) {
+func (i6 *Addr) BitsRange() (start, stop uint) {
 	highBits := bits.OnesCount64(i6.high)
 	lowBits := bits.OnesCount64(i6.low)
 
@@ -426,7 +278,7 @@ func (i6 *ipv6addr) BitsRange() (start, stop uint) {
 	return start, stop
 }
 
-func (i6 *ipv6addr) Inc() *ipv6addr {
+func (i6 *Addr) Inc() *Addr {
 	nl := i6.low + 1
 	//check carry
 	if i6.low > nl {
@@ -437,14 +289,14 @@ func (i6 *ipv6addr) Inc() *ipv6addr {
 			//carry with high
 			return nil
 		} else {
-			return &ipv6addr{nh, nl}
+			return &Addr{nh, nl, i6.zone}
 		}
 	} else {
-		return &ipv6addr{i6.high, nl}
+		return &Addr{i6.high, nl, i6.zone}
 	}
 }
 
-func (i6 *ipv6addr) Dec() *ipv6addr {
+func (i6 *Addr) Dec() *Addr {
 	nl := i6.low - 1
 	if i6.low < nl {
 		//carry, borrow from high
@@ -454,10 +306,10 @@ func (i6 *ipv6addr) Dec() *ipv6addr {
 			//carry on hight
 			return nil
 		} else {
-			return &ipv6addr{nh, nl}
+			return &Addr{nh, nl, i6.zone}
 		}
 	} else {
-		return &ipv6addr{i6.high, nl}
+		return &Addr{i6.high, nl, i6.zone}
 	}
 }
 
@@ -519,7 +371,7 @@ func findZerosInTokens(s []string) []zeros {
 	maxi := uint(0)
 	for i := range s {
 		maxi = uint(i)
-		if s[i] == "0" {
+		if isZeroToken(s[i]) {
 			if !inside {
 				inside = true
 				start = uint(i)
@@ -546,7 +398,7 @@ func findZerosInTokensExpose(s []string, exposeTokens []exposeInToken) []zeros {
 	maxi := uint(0)
 	for i := range s {
 		maxi = uint(i)
-		if s[i] == "0" && exposeTokens[i].empty {
+		if isZeroToken(s[i]) && exposeTokens[i].empty {
 			if !inside {
 				inside = true
 				start = uint(i)
@@ -566,6 +418,11 @@ func findZerosInTokensExpose(s []string, exposeTokens []exposeInToken) []zeros {
 	return ret
 }
 
+//isZeroToken tells if hex group is zero, padded or not
+func isZeroToken(s string) bool {
+	return len(s) > 0 && len(strings.TrimLeft(s, "0")) == 0
+}
+
 func removeZeroTokens(s []string) []string {
 	z := findBestZeros(findZerosInTokens(s))
 	if z.start == 0 && z.stop == 0 {
@@ -606,10 +463,17 @@ func removeZeroTokensExpose(s []string, exposeTokens []exposeInToken) []string {
 	return newS
 }
 
-func (i6 *ipv6addr) StringTokens(leadingZeros bool) []string {
+func (i6 *Addr) StringTokens(leadingZeros bool) []string {
+	c := DefaultHexPrintConfig()
+	c.LeadingZeros = leadingZeros
+	return i6.StringTokensConfig(c)
+}
+
+//StringTokensConfig returns 8 hex groups of the address formatted with c
+func (i6 *Addr) StringTokensConfig(c HexPrintConfig) []string {
 	s := make([]string, 8)
 	for i := range s {
-		s[i] = i6.asHexToken(7-i, leadingZeros)
+		s[i] = i6.asHexToken(7-i, c)
 	}
 	return s
 }
@@ -638,22 +502,21 @@ func tokenizeExpose(exposeHexStart, exposeHexEnd uint) []exposeInToken {
 			}
 		}
 	}
-	if leftExposeChar == rightExposeChar {
-		if s[0].empty == false && s[0].start == 0 {
-			s[0].contLeft = true
-		}
-		if s[7].empty == false && s[7].stop == 3 {
-			s[7].contRight = true
-		}
-	}
 	return s
 }
 
-func tokenizeMultiExpose(e []exposeChar) [][]exposeChar {
-	r := make([][]exposeChar, 8)
+//tokenizeMultiExpose splits chars by token and makes their positions local
+//to the token, chars past the last hex digit are dropped
+func tokenizeMultiExpose(e []ExposeChar) [][]ExposeChar {
+	r := make([][]ExposeChar, 8)
 	for i := range e {
-		toknum := e[i].position / 4
-		r[toknum] = append(r[toknum], e[i])
+		toknum := e[i].Position / 4
+		if toknum > 7 {
+			continue
+		}
+		local := e[i]
+		local.Position %= 4
+		r[toknum] = append(r[toknum], local)
 	}
 	return r
 }
@@ -669,131 +532,141 @@ func BitToHexNum(bit uint) uint {
 
 //bits are counted as mask, end bit is +1, works as array index
 //for example start = 10, end = 11 means that only 10 bit is exposed
-func (i6 *ipv6addr) StringTokensExpose(exposeTokens []exposeInToken) []string {
-	s := i6.StringTokens(false)
+func (i6 *Addr) StringTokensExpose(exposeTokens []exposeInToken, c HexPrintConfig) []string {
+	s := make([]string, 8)
 	for i := range s {
-		s[i] = i6.asHexTokenExpose(7-i, *(exposeTokens[i].localExpose(i)))
+		s[i] = i6.asHexTokenExpose(7-i, *(exposeTokens[i].localExpose(i)), c)
 	}
 	return s
 }
 
-func (i6 ipv6addr) String() string {
-	s := i6.StringTokens(false)
+func (i6 Addr) String() string {
+	return i6.StringConfig(DefaultHexPrintConfig())
+}
+
+//StringConfig formats address with c, runs of zero groups are compressed
+//with :: also when groups are padded
+func (i6 *Addr) StringConfig(c HexPrintConfig) string {
+	s := i6.StringTokensConfig(c)
 	s = removeZeroTokens(s)
-	return strings.Join(s, ":")
+	return strings.Join(s, ":") + i6.zoneSuffix()
+}
+
+func (i6 *Addr) LongString() string {
+	return i6.LongStringConfig(DefaultHexPrintConfig())
+}
+
+//LongStringConfig formats all 8 groups padded to 4 digits, only Upcase of c
+//is used
+func (i6 *Addr) LongStringConfig(c HexPrintConfig) string {
+	c.LeadingZeros = true
+	s := i6.StringTokensConfig(c)
+	return strings.Join(s, ":") + i6.zoneSuffix()
 }
 
-func (i6 *ipv6addr) LongString() string {
-	s := i6.StringTokens(true)
-	return strings.Join(s, ":")
+func (i6 *Addr) ExposeString(exposeBitStart, exposeBitEnd uint) string {
+	return i6.ExposeStringConfig(exposeBitStart, exposeBitEnd, DefaultHexPrintConfig())
 }
 
-func (i6 *ipv6addr) ExposeString(exposeBitStart, exposeBitEnd uint) string {
+//ExposeStringConfig works like ExposeString with hex digits and expose
+//markers taken from c
+func (i6 *Addr) ExposeStringConfig(exposeBitStart, exposeBitEnd uint, c HexPrintConfig) string {
 	es := BitToHexNum(exposeBitStart)
 	ee := BitToHexNum(exposeBitEnd)
 
 	te := tokenizeExpose(es, ee)
 
-	s := i6.StringTokensExpose(te)
+	s := i6.StringTokensExpose(te, c)
 	s = removeZeroTokensExpose(s, te)
-	return strings.Join(s, ":")
+	return strings.Join(s, ":") + i6.zoneSuffix()
 }
 
-func (i6 *ipv6addr) MultiExposeString(exposes []exposeChar) string {
-	s := i6.StringTokens(false)
-	s = removeZeroTokens(s)
-	return strings.Join(s, ":")
+//MultiExposeString places every char of exposes around its hex digit, zero
+//groups holding a char are not compressed with ::
+func (i6 *Addr) MultiExposeString(exposes []ExposeChar) string {
+	return i6.MultiExposeStringConfig(exposes, DefaultHexPrintConfig())
 }
 
-func makeIPv6Addr(t ipv6tokenized) (i6 ipv6addr, e error) {
-	if len(t) != 8 {
-		return ipv6addr{0, 0}, errors.New("ipv6tokenized should have exactly 8 tokens")
-	}
-
-	high, err := hexStringToInt(mergeTokens(t[0:4]))
-	if err != nil {
-		return ipv6addr{0, 0}, err
-	}
-	low, err := hexStringToInt(mergeTokens(t[4:8]))
-	if err != nil {
-		return ipv6addr{0, 0}, err
-	}
-
-	return ipv6addr{high, low}, nil
+//MultiExposeStringConfig works like MultiExposeString with digits printed
+//using c, expose chars of c are not used
+func (i6 *Addr) MultiExposeStringConfig(exposes []ExposeChar, c HexPrintConfig) string {
+	return i6.multiExposeString(exposes, make([]uint, 8), c)
 }
 
-//IPv6Addr ...
-func makeIPv6AddrFromString(s string) (i6 ipv6addr, e error) {
-	t, err := tokenizeIPv6(s)
-	if err != nil {
-		return ipv6addr{0, 0}, err
-	}
-	tt := makeTokens(t)
-	return makeIPv6Addr(tt)
+//MultiExposeRangeString marks every range like ExposeString does, each
+//with its own chars; zero groups inside ranges are not compressed with ::
+func (i6 *Addr) MultiExposeRangeString(ranges []ExposeRange) string {
+	return i6.MultiExposeRangeStringConfig(ranges, DefaultHexPrintConfig())
 }
 
-func makeIPv6AddrFromString2(s string) (i6 *ipv6addr, e error) {
-	ss := strings.Split(s, ":")
-	if len(ss) > 8 {
-		return nil, errors.New("too many colons in address")
-	}
-	empty := false
-	if ss[0] == "" {
-		ss[0] = "0"
-	}
-	if ss[len(ss)-1] == "" {
-		ss[len(ss)-1] = "0"
-	}
-	for _, v := range ss {
-		if len(v) == 0 {
-			if empty {
-				return nil, errors.New("detected more than one double colon")
-			}
-			empty = true
+//MultiExposeRangeStringConfig works like MultiExposeRangeString with digits
+//printed using c, ranges without chars use expose chars of c
+func (i6 *Addr) MultiExposeRangeStringConfig(ranges []ExposeRange, c HexPrintConfig) string {
+	exposes := make([]ExposeChar, 0, 2*len(ranges))
+	minDigits := make([]uint, 8)
+	for _, r := range ranges {
+		start, end := BitToHexNum(r.StartBit), BitToHexNum(r.EndBit)
+		if start > 31 || end > 31 || start > end {
+			continue
 		}
-	}
-	if empty == false && len(ss) != 8 {
-		return nil, errors.New("too short address when there is no double colon")
-	}
-	addr := ipv6addr{0, 0}
-	for i := 0; i < len(ss); i++ {
-		if ss[i] == "" {
-			break
+		sc, ec := r.StartChar, r.EndChar
+		if sc == 0 {
+			sc = c.ExposeStartChar
 		}
-		a, err := strconv.ParseUint(ss[i], 16, 16)
-		if err != nil {
-			return nil, err
+		if ec == 0 {
+			ec = c.ExposeEndChar
 		}
-		if i < 4 {
-			addr.high = addr.high + (a << (16 * (3 - i)))
-		} else {
-			addr.low = addr.low + (a << (16 * (7 - i)))
+		exposes = append(exposes, ExposeChar{true, start, sc}, ExposeChar{false, end, ec})
+		//all digits inside the range are printed
+		for d := start; d <= end; d++ {
+			if 4-d%4 > minDigits[d/4] {
+				minDigits[d/4] = 4 - d%4
+			}
 		}
 	}
-	//there is an doublecolon (empty token) we have to walk address in reverse order
-	if empty {
-		for i := 0; i < len(ss); i++ {
-			ssind := len(ss) - i - 1
-			if ss[ssind] == "" {
-				break
-			}
-			a, err := strconv.ParseUint(ss[ssind], 16, 16)
-			if err != nil {
-				return nil, err
-			}
-			if i > 3 {
-				addr.high = addr.high + (a << (16 * (i - 4)))
-			} else {
-				addr.low = addr.low + (a << (16 * i))
-			}
+	return i6.multiExposeString(exposes, minDigits, c)
+}
+
+//multiExposeString formats address with exposes, every token prints at least
+//minDigits digits, tokens with chars or minDigits are kept out of ::
+//compression
+func (i6 *Addr) multiExposeString(exposes []ExposeChar, minDigits []uint, c HexPrintConfig) string {
+	tm := tokenizeMultiExpose(exposes)
+	te := make([]exposeInToken, 8)
+	s := make([]string, 8)
+	for i := range s {
+		te[i].empty = len(tm[i]) == 0 && minDigits[i] == 0
+		num := i6.low
+		token := 7 - i
+		if token > 3 {
+			num = i6.high
+			token -= 4
 		}
+		s[i] = toHexTokenMultiExpose(num, token, tm[i], minDigits[i], c)
 	}
-	return &addr, nil
+	s = removeZeroTokensExpose(s, te)
+	return strings.Join(s, ":") + i6.zoneSuffix()
+}
+
+//AddrFromUint64 builds an address from its upper and lower 64 bits
+func AddrFromUint64(high, low uint64) Addr {
+	return Addr{high: high, low: low}
+}
+
+//High returns upper 64 bits of the address
+func (i6 *Addr) High() uint64 {
+	return i6.high
+}
+
+//Low returns lower 64 bits of the address
+func (i6 *Addr) Low() uint64 {
+	return i6.low
 }
 
-func makeIPv6AddrFromMask(mask uint) (i6 ipv6addr, e error) {
+//AddrFromMask returns netmask for given prefix length
+func AddrFromMask(mask uint) (i6 Addr, e error) {
 	if mask > 128 || mask < 0 {
-		return ipv6addr{}, errors.New("incorrect mask")
+		return Addr{}, errors.New("incorrect mask")
 	}
 	//high
 	var h uint64
@@ -809,37 +682,32 @@ func makeIPv6AddrFromMask(mask uint) (i6 ipv6addr, e error) {
 	} else {
 		l = 0xFFFFFFFFFFFFFFFF << (128 - mask)
 	}
-	return ipv6addr{h, l}, nil
+	return Addr{high: h, low: l}, nil
 }
 
-func makeIPv6PrefixFromString(s string) (prefix *ipv6prefix, e error) {
-	ss := strings.Split(s, "/")
-	if len(ss) > 2 {
-		return nil, errors.New("too many / in prefix")
+//NewPrefix builds a prefix from an address and prefix length,
+//host bits of the address are kept
+func NewPrefix(a Addr, mask uint) (*Prefix, error) {
+	if mask > 128 {
+		return nil, errors.New("mask is too long")
 	}
-	var mask uint64
-	if len(ss) == 2 {
-		mask, e = strconv.ParseUint(ss[1], 10, 32)
-		if e != nil {
-			return nil, e
-		}
-		if mask > 128 {
-			return nil, errors.New("mask is too long")
-		}
+	return &Prefix{a, mask, nil}, nil
+}
 
-	} else {
-		mask = 128
-	}
-	i6, err := makeIPv6AddrFromString2(ss[0])
-	if err != nil {
-		return nil, err
-	}
-	return &ipv6prefix{*i6, uint(mask), nil}, nil
+//Addr returns address of the prefix as it was given, including host bits
+func (p *Prefix) Addr() Addr {
+	return p.addr
+}
+
+//Mask returns prefix length
+func (p *Prefix) Mask() uint {
+	return p.mask
 }
 
-func (p *ipv6prefix) getAddrMask() *ipv6addr {
+//AddrMask returns netmask of the prefix
+func (p *Prefix) AddrMask() *Addr {
 	if p.addrMask == nil {
-		am, err := makeIPv6AddrFromMask(p.mask)
+		am, err := AddrFromMask(p.mask)
 		if err == nil {
 			p.addrMask = &am
 		}
@@ -847,194 +715,55 @@ func (p *ipv6prefix) getAddrMask() *ipv6addr {
 	return p.addrMask
 }
 
-func (p *ipv6prefix) firstAddressFromSubnet() *ipv6addr {
-	return p.addr.And(p.getAddrMask())
+func (p *Prefix) FirstAddressFromSubnet() *Addr {
+	return p.addr.And(p.AddrMask())
 }
 
-func (p *ipv6prefix) lastAddressFromSubnet() *ipv6addr {
-	return p.addr.Or(p.getAddrMask().Neg())
+func (p *Prefix) LastAddressFromSubnet() *Addr {
+	return p.addr.Or(p.AddrMask().Neg())
 }
 
-func (p *ipv6prefix) nextPrefix() *ipv6prefix {
-	nextaddr := p.lastAddressFromSubnet().Inc()
+func (p *Prefix) NextPrefix() *Prefix {
+	nextaddr := p.LastAddressFromSubnet().Inc()
 	if nextaddr == nil {
 		return nil
 	}
-	return &ipv6prefix{*nextaddr, p.mask, nil}
+	return &Prefix{*nextaddr, p.mask, nil}
 }
 
-func (p *ipv6prefix) makeSubnetAddress() *ipv6prefix {
-	p.addr = *p.firstAddressFromSubnet()
+func (p *Prefix) MakeSubnetAddress() *Prefix {
+	p.addr = *p.FirstAddressFromSubnet()
 	return p
 }
 
-func (p *ipv6prefix) prevPrefix() *ipv6prefix {
-	prevaddr := p.firstAddressFromSubnet().Dec()
+func (p *Prefix) PrevPrefix() *Prefix {
+	prevaddr := p.FirstAddressFromSubnet().Dec()
 	if prevaddr == nil {
 		return nil
 	}
-	newprefix := ipv6prefix{*prevaddr, p.mask, nil}
-	return newprefix.makeSubnetAddress()
+	newprefix := Prefix{*prevaddr, p.mask, nil}
+	return newprefix.MakeSubnetAddress()
 }
 
-func (p *ipv6prefix) String() string {
+func (p *Prefix) String() string {
 	return fmt.Sprintf("%v/%v", p.addr, p.mask)
 }
 
-func (p *ipv6prefix) SubnetString() string {
-	return fmt.Sprintf("%v/%v", p.firstAddressFromSubnet(), p.mask)
+func (p *Prefix) SubnetString() string {
+	return fmt.Sprintf("%v/%v", p.FirstAddressFromSubnet(), p.mask)
 }
 
-func (p *ipv6prefix) ExposeString(exposeBitStart, exposeBitEnd uint) string {
+func (p *Prefix) ExposeString(exposeBitStart, exposeBitEnd uint) string {
 	return fmt.Sprintf("%v/%v", p.addr.ExposeString(exposeBitStart, exposeBitEnd), p.mask)
 }
 
-func main() {
-	t := cleanToken()
-
-	t.pushHexChar('a')
-	t.pushHexChar('9')
-	t.pushHexChar('x')
-	// for i := 0; i < 10; i++ {
-	// 	t.pushHexChar('0' + byte(i))
-	// }
-	fmt.Println(t)
-
-	t = tokenFromString("869ąłś")
-	fmt.Println(t)
-
-	fmt.Println(tokenizeIPv6("342:356:34234:342:23434:3223"))
-	fmt.Println(tokenizeIPv6("342:356:34234::23434:3223"))
-	fmt.Println(tokenizeIPv6("342:356:34234::23434::3223"))
-	fmt.Println(tokenizeIPv6("342:356:34234:::23434:3223"))
-	fmt.Println(tokenizeIPv6("342:356:34234::aaa:a:23434:3223"))
-
-	tests := []string{"342:356:4234::3223",
-		"0:a::",
-		"0:a::f",
-		"23:33:ffff::0:",
-		"aa::1:0:0:0:1",
-		"a:a:a:a:a:a:a:a",
-		"a:0:a:0:a:0:a:0",
-		"FFFF:ffff:ffff::",
-		"::"}
-	testmasks := []uint{1, 5, 64, 100, 126}
-	for _, x := range tests {
-		fmt.Print("Input: ")
-		fmt.Println(x)
-		i6, err := makeIPv6AddrFromString2(x)
-		if err != nil {
-			fmt.Println(err)
-		} else {
-			s := i6.StringTokens(false)
-			fmt.Print("Println(findZerosInTokens(s)): ")
-			fmt.Println(findZerosInTokens(s))
-			fmt.Print("Println(findBestZeros(findZerosInTokens(s))): ")
-			fmt.Println(findBestZeros(findZerosInTokens(s)))
-			fmt.Print("Println(i6): ")
-			fmt.Println(i6)
-			fmt.Print("Println(i6.asHex()): ")
-			fmt.Println(i6.asHex())
-			fmt.Print("Println(i6.asBigInt()): ")
-			fmt.Println(i6.asBigInt())
-			fmt.Print("Println(i6.LongString()): ")
-			fmt.Println(i6.LongString())
-			for _, mask := range testmasks {
-				m, _ := makeIPv6AddrFromMask(mask)
-				fmt.Printf("ANDing with mask %v : ", mask)
-				fmt.Println(*(i6.And(&m)))
-				fmt.Print("ORing with negmask: ")
-				fmt.Println(*(i6.Or((&m).Neg())))
-			}
-		}
-	}
-	for i := 0; i <= 128; i += 4 {
-		fmt.Printf("mask %v: ", i)
-		m, err := makeIPv6AddrFromMask(uint(i))
-		if err != nil {
-			fmt.Println(err)
-		} else {
-			fmt.Print(m)
-			fmt.Print(" neg: ")
-			fmt.Println(*(m.Neg()))
-		}
-	}
-	tests2 := []string{"342:356:4234::3223/120",
-		"0:a::/40",
-		"0:a::f/32",
-		"23:33:ffff::0:/48",
-		"aa::1:0:0:0:1",
-		"a:a:a:a:a:a:a:a/0",
-		"a:0:a:0:a:0:a:0/13",
-		"FFFF:ffff:ffff::/30",
-		"::/39"}
-	for _, x := range tests2 {
-		fmt.Print("Input: ")
-		fmt.Println(x)
-		p, err := makeIPv6PrefixFromString(x)
-		if err != nil {
-			fmt.Println(err)
-		} else {
-			fmt.Println(p)
-			fmt.Print("First: ")
-			fmt.Println(p.firstAddressFromSubnet())
-			fmt.Print("Last:  ")
-			fmt.Println(p.lastAddressFromSubnet())
-			pp := p.prevPrefix()
-			if pp != nil {
-				fmt.Print("Prev:  ")
-				fmt.Println(pp)
-			} else {
-				fmt.Println("No prev.")
-			}
-
-			np := p.nextPrefix()
-			if np != nil {
-				fmt.Print("Next:  ")
-				fmt.Println(np)
-			} else {
-				fmt.Println("No next.")
-			}
-		}
-	}
-	test4, _ := makeIPv6AddrFromString("0:a::")
-	for i := 58; i < 68; i += 1 {
-		for j := i; j < 68; j += 1 {
-			hi := BitToHexNum(uint(i))
-			hj := BitToHexNum(uint(j))
-			fmt.Printf("i: %v, j: %#v, hi: %v, hj: %v -> ", i, j, hi, hj)
-			//te := tokenizeExpose(hi, hj)
-			//fmt.Printf("%+v\n", te)
-			fmt.Println(test4.ExposeString(uint(i), uint(j)))
-			// for i := range te {
-			// 	te[i] = *(te[i].localExpose(i))
-			// }
-			// fmt.Printf("i: %v, j: %#v, hi: %v, hj: %v -> ", i, j, hi, hj)
-			// fmt.Printf("%+v\n", te)
-		}
-	}
-	test5, _ := makeIPv6PrefixFromString("0:1:1:1::/64")
-	cum := &ipv6addr{}
-	fmt.Printf("Starting from: %v\n", test5)
-	addrs := make([]*ipv6prefix, 0, 21)
-	for i := 0; i < 20; i++ {
-		test5n := test5.nextPrefix()
-		cum = cum.CummulativeXor(&test5.addr, &test5n.addr)
-		test5 = test5n
-		addrs = append(addrs, test5)
-	}
-	fmt.Printf("Cummulative XOR: %v\n", cum)
-	start, end := cum.BitsRange()
-	fmt.Printf("Bits from CumulativeXOR: %v - %v\n", start, end)
-	for i, v := range addrs {
-		fmt.Printf("Next %02v: %v", i, v.ExposeString(start, end))
-		if i > 0 {
-			fmt.Printf(" bitdiff: %v\n", (addrs[i-1].addr.Xor(&v.addr)).LongString())
-		} else {
-			fmt.Printf("\n")
-		}
-	}
+//StringConfig formats prefix with address printed using c
+func (p *Prefix) StringConfig(c HexPrintConfig) string {
+	return fmt.Sprintf("%v/%v", p.addr.StringConfig(c), p.mask)
+}
 
-	e1 := []exposeChar{exposeChar{true, 2, '_'}, exposeChar{false, 1, '^'}}
-	fmt.Println(toHexTokenMultiExpose(uint64(1024), 0, e1))
+//ExposeStringConfig formats prefix with address printed by
+//Addr.ExposeStringConfig
+func (p *Prefix) ExposeStringConfig(exposeBitStart, exposeBitEnd uint, c HexPrintConfig) string {
+	return fmt.Sprintf("%v/%v", p.addr.ExposeStringConfig(exposeBitStart, exposeBitEnd, c), p.mask)
 }
@@ -0,0 +1,28 @@
+package ipv6calc
+
+import "testing"
+
+func TestExposeStringConfig(t *testing.T) {
+	def := DefaultHexPrintConfig()
+	same := DefaultHexPrintConfig()
+	same.ExposeStartChar = '|'
+	same.ExposeEndChar = '|'
+	tests := []struct {
+		addr       string
+		start, end uint
+		c          HexPrintConfig
+		want       string
+	}{
+		{"2001:db8::", 0, 15, def, "<2001>:db8::"},
+		{"2001:db8::", 16, 31, def, "2001:<0db8>::"},
+		{"2001:db8::", 0, 15, same, "|2001|:db8::"},
+		{"2001:db8::1", 112, 127, same, "2001:db8::|0001|"},
+		{"2001:db8::", 0, 127, same, "|2001:0db8:0000:0000:0000:0000:0000:0000|"},
+		{"2001:db8::", 24, 31, same, "2001:d|b8|::"},
+	}
+	for _, tt := range tests {
+		if got := mustAddr(t, tt.addr).ExposeStringConfig(tt.start, tt.end, tt.c); got != tt.want {
+			t.Errorf("ExposeStringConfig(%v, %v, %v, %q%q) = %v, want %v", tt.addr, tt.start, tt.end, tt.c.ExposeStartChar, tt.c.ExposeEndChar, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ipv6calc
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+//Bits of the multicast flags field, RFC 4291, RFC 3306 and RFC 3956
+const (
+	MulticastTransient  = 0x1
+	MulticastPrefix     = 0x2
+	MulticastEmbeddedRP = 0x4
+)
+
+var scopeNames = map[uint8]string{
+	0x0: "reserved",
+	0x1: "interface-local",
+	0x2: "link-local",
+	0x3: "realm-local",
+	0x4: "admin-local",
+	0x5: "site-local",
+	0x8: "organization-local",
+	0xe: "global",
+	0xf: "reserved",
+}
+
+//ScopeName returns name of a multicast scope value as defined by RFC 7346
+func ScopeName(scope uint8) string {
+	if n, ok := scopeNames[scope&0xf]; ok {
+		return n
+	}
+	return "unassigned"
+}
+
+//MulticastInfo holds decoded fields of a multicast address, Prefix is set
+//for unicast-prefix-based addresses (RFC 3306) and RP for embedded-RP
+//addresses (RFC 3956), GroupID is then only the last 32 bits
+type MulticastInfo struct {
+	Flags   uint8
+	Scope   uint8
+	GroupID *big.Int
+	Prefix  *Prefix
+	RP      *Addr
+}
+
+//IsMulticast checks if address is in ff00::/8
+func (i6 *Addr) IsMulticast() bool {
+	return i6.high>>56 == 0xff
+}
+
+//Multicast decodes a multicast address, it fails for other addresses and for
+//prefix based addresses with prefix length over 64
+func (i6 *Addr) Multicast() (*MulticastInfo, error) {
+	if !i6.IsMulticast() {
+		return nil, errors.New("not a multicast address")
+	}
+	m := &MulticastInfo{
+		Flags: uint8(i6.high>>52) & 0xf,
+		Scope: uint8(i6.high>>48) & 0xf,
+	}
+	if m.Flags&MulticastPrefix == 0 {
+		if m.Flags&MulticastEmbeddedRP != 0 {
+			return nil, errors.New("embedded-RP flag set without prefix flag")
+		}
+		m.GroupID = new(big.Int).Lsh(new(big.Int).SetUint64(i6.high&0xffffffffffff), 64)
+		m.GroupID.Or(m.GroupID, new(big.Int).SetUint64(i6.low))
+		return m, nil
+	}
+	//ff | flags | scope | rsvd | riid | plen | prefix (64) | group id (32)
+	plen := uint(i6.high>>32) & 0xff
+	if plen > 64 {
+		return nil, fmt.Errorf("embedded prefix length %v is over 64", plen)
+	}
+	prefix := Addr{high: i6.high<<32 | i6.low>>32}
+	m.Prefix = &Prefix{*prefix.And(&Addr{high: maskHigh(plen)}), plen, nil}
+	m.GroupID = new(big.Int).SetUint64(i6.low & 0xffffffff)
+	if m.Flags&MulticastEmbeddedRP != 0 {
+		riid := (i6.high >> 40) & 0xf
+		m.RP = &Addr{high: m.Prefix.addr.high, low: riid}
+	}
+	return m, nil
+}
+
+//maskHigh returns upper half of a netmask of length up to 64
+func maskHigh(mask uint) uint64 {
+	if mask == 0 {
+		return 0
+	}
+	return ^uint64(0) << (64 - mask)
+}
+
+//SourceSpecific checks if address is in the RFC 3306 source-specific range
+//ff3x::/32
+func (m *MulticastInfo) SourceSpecific() bool {
+	return m.Prefix != nil && m.RP == nil && m.Prefix.mask == 0 && m.Prefix.addr.high == 0
+}
@@ -0,0 +1,66 @@
+package ipv6calc
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestMulticast(t *testing.T) {
+	tests := []struct {
+		addr    string
+		flags   uint8
+		scope   uint8
+		group   int64
+		prefix  string
+		rp      string
+		ssm     bool
+		invalid bool
+	}{
+		{addr: "ff02::1", flags: 0, scope: 2, group: 1},
+		{addr: "ff15::1:3", flags: MulticastTransient, scope: 5, group: 0x10003},
+		//RFC 3306 unicast-prefix-based, plen 0x30
+		{addr: "ff3e:30:2001:db8:1234::8000:1", flags: 3, scope: 0xe, group: 0x80000001, prefix: "2001:db8:1234::/48"},
+		//bits past plen are not part of the prefix
+		{addr: "ff3e:20:2001:db8:ffff::1", flags: 3, scope: 0xe, group: 1, prefix: "2001:db8::/32"},
+		{addr: "ff3e::8000:1", flags: 3, scope: 0xe, group: 0x80000001, prefix: "::/0", ssm: true},
+		//RFC 3956 embedded-RP, RIID 1 and plen 0x40
+		{addr: "ff7e:140:2001:db8:beef:feed::1234", flags: 7, scope: 0xe, group: 0x1234, prefix: "2001:db8:beef:feed::/64", rp: "2001:db8:beef:feed::1"},
+		{addr: "ff78:f20:2001:db8::5", flags: 7, scope: 8, group: 5, prefix: "2001:db8::/32", rp: "2001:db8::f"},
+		{addr: "ff3e:41:2001:db8::1", invalid: true},
+		{addr: "ff4e::1", invalid: true},
+		{addr: "2001:db8::1", invalid: true},
+	}
+	for _, tt := range tests {
+		m, err := mustAddr(t, tt.addr).Multicast()
+		if tt.invalid {
+			if err == nil {
+				t.Errorf("Multicast(%v) succeeded", tt.addr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Multicast(%v) error %v", tt.addr, err)
+			continue
+		}
+		if m.Flags != tt.flags || m.Scope != tt.scope || m.GroupID.Cmp(big.NewInt(tt.group)) != 0 {
+			t.Errorf("Multicast(%v) = flags %x scope %x group %x, want %x %x %x", tt.addr, m.Flags, m.Scope, m.GroupID, tt.flags, tt.scope, tt.group)
+		}
+		prefix, rp := "", ""
+		if m.Prefix != nil {
+			prefix = m.Prefix.CanonicalString()
+		}
+		if m.RP != nil {
+			rp = m.RP.CanonicalString()
+		}
+		if prefix != tt.prefix || rp != tt.rp || m.SourceSpecific() != tt.ssm {
+			t.Errorf("Multicast(%v) = prefix %q rp %q ssm %v, want %q %q %v", tt.addr, prefix, rp, m.SourceSpecific(), tt.prefix, tt.rp, tt.ssm)
+		}
+	}
+	if ScopeName(2) != "link-local" || ScopeName(0xe) != "global" || ScopeName(6) != "unassigned" {
+		t.Errorf("ScopeName returns wrong names")
+	}
+	m, _ := mustAddr(t, "ff0e:1:2:3:4:5:6:7").Multicast()
+	if want, _ := new(big.Int).SetString("1000200030004000500060007", 16); m.GroupID.Cmp(want) != 0 {
+		t.Errorf("112 bit group ID = %x, want %x", m.GroupID, want)
+	}
+}
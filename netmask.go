@@ -0,0 +1,95 @@
+package ipv6calc
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+//ErrNonContiguousMask is wrapped by MaskError, test for it with errors.Is
+var ErrNonContiguousMask = errors.New("invalid mask")
+
+//MaskError reports a netmask that does not start at bit 0 or a wildcard mask
+//that does not end at bit 127, or one with a hole; Start and Stop are the
+//outermost set bits as returned by BitsRange, Hole is the first bit which
+//breaks the run of ones
+type MaskError struct {
+	Start, Stop uint
+	Hole        uint
+	Wildcard    bool
+}
+
+func (e *MaskError) Error() string {
+	switch {
+	case !e.Wildcard && e.Start != 0:
+		return fmt.Sprintf("%v: netmask must start at bit 0, set bits are %v-%v", ErrNonContiguousMask, e.Start, e.Stop)
+	case e.Wildcard && e.Stop != 127:
+		return fmt.Sprintf("%v: wildcard mask must end at bit 127, set bits are %v-%v", ErrNonContiguousMask, e.Start, e.Stop)
+	case e.Wildcard:
+		return fmt.Sprintf("%v: wildcard mask has a hole at bit %v, set bits are %v-%v", ErrNonContiguousMask, e.Hole, e.Start, e.Stop)
+	}
+	return fmt.Sprintf("%v: netmask has a hole at bit %v, set bits are %v-%v", ErrNonContiguousMask, e.Hole, e.Start, e.Stop)
+}
+
+func (e *MaskError) Unwrap() error {
+	return ErrNonContiguousMask
+}
+
+//leadingOnes counts set bits from the most significant one
+func (i6 *Addr) leadingOnes() uint {
+	if i6.high == ^uint64(0) {
+		return 64 + uint(bits.LeadingZeros64(^i6.low))
+	}
+	return uint(bits.LeadingZeros64(^i6.high))
+}
+
+//trailingOnes counts set bits from the least significant one
+func (i6 *Addr) trailingOnes() uint {
+	if i6.low == ^uint64(0) {
+		return 64 + uint(bits.TrailingZeros64(^i6.high))
+	}
+	return uint(bits.TrailingZeros64(^i6.low))
+}
+
+//onesCount counts all set bits
+func (i6 *Addr) onesCount() uint {
+	return uint(bits.OnesCount64(i6.high) + bits.OnesCount64(i6.low))
+}
+
+//MaskLength returns prefix length of a netmask like ffff:ffff:ffff::
+func (i6 *Addr) MaskLength() (uint, error) {
+	n := i6.leadingOnes()
+	if n != i6.onesCount() {
+		start, stop := i6.BitsRange()
+		return 0, &MaskError{start, stop, n, false}
+	}
+	return n, nil
+}
+
+//WildcardLength returns prefix length of a Cisco style wildcard (inverse)
+//mask like ::ffff:ffff:ffff:ffff:ffff
+func (i6 *Addr) WildcardLength() (uint, error) {
+	n := i6.trailingOnes()
+	if n != i6.onesCount() {
+		start, stop := i6.BitsRange()
+		return 0, &MaskError{start, stop, 127 - n, true}
+	}
+	return 128 - n, nil
+}
+
+//maskOrWildcardLength accepts both kinds of masks, all zeros and all ones
+//are ambiguous and read as netmasks (/0 and /128); masks ending at bit 127
+//but not starting at bit 0 are reported as wildcard masks
+func (i6 *Addr) maskOrWildcardLength() (uint, error) {
+	if i6.high>>63 == 0 && i6.low&1 == 1 {
+		return i6.WildcardLength()
+	}
+	return i6.MaskLength()
+}
+
+//ParsePrefixWildcard works like ParsePrefix, but reads a mask given as an
+//address after the slash always as a wildcard mask, so that :: means a single
+//host as in Cisco ACLs
+func ParsePrefixWildcard(s string) (*Prefix, error) {
+	return parsePrefix(s, (*Addr).WildcardLength)
+}
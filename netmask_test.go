@@ -0,0 +1,57 @@
+package ipv6calc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParsePrefixMask(t *testing.T) {
+	tests := []struct {
+		in   string
+		mask uint
+		err  string
+	}{
+		{"2001:db8::/ffff:ffff:ffff::", 48, ""},
+		{"2001:db8::/::ffff:ffff:ffff:ffff:ffff", 48, ""},
+		{"2001:db8::/::", 0, ""},
+		{"2001:db8::/ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", 128, ""},
+		{"2001:db8::/0:0:ffff::", 0, "invalid mask: netmask must start at bit 0, set bits are 32-47"},
+		{"2001:db8::/ffff:ff00:ffff::", 0, "invalid mask: netmask has a hole at bit 24, set bits are 0-47"},
+		{"2001:db8::/::ff0f", 0, "invalid mask: wildcard mask has a hole at bit 123, set bits are 112-127"},
+	}
+	for _, tt := range tests {
+		p, err := ParsePrefix(tt.in)
+		if tt.err == "" {
+			if err != nil || p.Mask() != tt.mask {
+				t.Errorf("ParsePrefix(%q) = %v, %v, want /%v", tt.in, p, err, tt.mask)
+			}
+			continue
+		}
+		var me *MaskError
+		if !errors.As(err, &me) || !errors.Is(err, ErrNonContiguousMask) || me.Error() != tt.err {
+			t.Errorf("ParsePrefix(%q) error = %v, want %v", tt.in, err, tt.err)
+		}
+	}
+}
+
+func TestParsePrefixWildcard(t *testing.T) {
+	tests := []struct {
+		in   string
+		mask uint
+	}{
+		{"2001:db8::1/::", 128},
+		{"2001:db8::/ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", 0},
+		{"2001:db8::/::ff", 120},
+	}
+	for _, tt := range tests {
+		p, err := ParsePrefixWildcard(tt.in)
+		if err != nil || p.Mask() != tt.mask {
+			t.Errorf("ParsePrefixWildcard(%q) = %v, %v, want /%v", tt.in, p, err, tt.mask)
+		}
+	}
+	_, err := ParsePrefixWildcard("2001:db8::/ffff::")
+	var me *MaskError
+	if !errors.As(err, &me) || !me.Wildcard || me.Stop != 15 {
+		t.Errorf("ParsePrefixWildcard(2001:db8::/ffff::) error = %v", err)
+	}
+}
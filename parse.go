@@ -0,0 +1,241 @@
+package ipv6calc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+//Reasons reported by ParseError, test them with errors.Is
+var (
+	ErrTooManyGroups       = errors.New("too many groups")
+	ErrTooFewGroups        = errors.New("too few groups without double colon")
+	ErrMultipleDoubleColon = errors.New("more than one double colon")
+	ErrGroupTooLong        = errors.New("group longer than 4 hex digits")
+	ErrEmptyGroup          = errors.New("empty group")
+	ErrInvalidRune         = errors.New("invalid character")
+	ErrInvalidIPv4         = errors.New("invalid embedded ipv4 address")
+	ErrEmptyZone           = errors.New("empty zone identifier")
+	ErrZoneNotAllowed      = errors.New("zone identifier on address that is not link-local")
+	ErrInvalidPrefixLength = errors.New("invalid prefix length")
+	ErrInvalidRange        = errors.New("missing - between first and last address")
+	ErrRangeOrder          = errors.New("last address of range is before first")
+)
+
+//ParseError describes why and where parsing of an address or prefix failed,
+//Column counts characters (not bytes) from 1
+type ParseError struct {
+	Input  string
+	Column int
+	Reason error
+}
+
+func (e *ParseError) Error() string {
+	if e.Column > 0 && e.Column <= utf8.RuneCountInString(e.Input) {
+		r := []rune(e.Input)[e.Column-1]
+		return fmt.Sprintf("%q column %v (%q): %v", e.Input, e.Column, r, e.Reason)
+	}
+	return fmt.Sprintf("%q column %v: %v", e.Input, e.Column, e.Reason)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Reason
+}
+
+func newParseError(input string, pos int, reason error) *ParseError {
+	return &ParseError{input, utf8.RuneCountInString(input[:pos]) + 1, reason}
+}
+
+//scanAddr parses input[start:end] as an address with optional %zone,
+//errors point into the whole input
+func scanAddr(input string, start, end int) (*Addr, error) {
+	zone := ""
+	if i := strings.IndexByte(input[start:end], '%'); i >= 0 {
+		zone = input[start+i+1 : end]
+		if len(zone) == 0 {
+			return nil, newParseError(input, start+i, ErrEmptyZone)
+		}
+		end = start + i
+	}
+
+	groups := make([]uint16, 0, 8)
+	ellipsis := -1
+	pos := start
+	if strings.HasPrefix(input[pos:end], "::") {
+		ellipsis = 0
+		pos += 2
+	} else if pos < end && input[pos] == ':' {
+		return nil, newParseError(input, pos, ErrEmptyGroup)
+	}
+	for pos < end {
+		limit := 8
+		if ellipsis >= 0 {
+			limit = 7
+		}
+		gstart := pos
+		val := uint16(0)
+		for pos < end && checkHexChar(input[pos]) {
+			if pos-gstart == 4 {
+				return nil, newParseError(input, pos, ErrGroupTooLong)
+			}
+			val = val<<4 | uint16(hexToInt(input[pos]))
+			pos++
+		}
+		if pos < end && input[pos] == '.' {
+			if len(groups)+2 > limit {
+				return nil, newParseError(input, gstart, ErrTooManyGroups)
+			}
+			v4, err := scanDottedQuad(input, gstart, end)
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, uint16(v4>>16), uint16(v4))
+			pos = end
+			break
+		}
+		if pos == gstart {
+			if pos == end {
+				return nil, newParseError(input, pos-1, ErrEmptyGroup)
+			}
+			if input[pos] == ':' {
+				return nil, newParseError(input, pos, ErrEmptyGroup)
+			}
+			return nil, newParseError(input, pos, ErrInvalidRune)
+		}
+		if len(groups) == limit {
+			return nil, newParseError(input, gstart, ErrTooManyGroups)
+		}
+		groups = append(groups, val)
+		if pos == end {
+			break
+		}
+		if input[pos] != ':' {
+			return nil, newParseError(input, pos, ErrInvalidRune)
+		}
+		pos++
+		if pos == end {
+			return nil, newParseError(input, pos-1, ErrEmptyGroup)
+		}
+		if input[pos] == ':' {
+			if ellipsis >= 0 {
+				return nil, newParseError(input, pos-1, ErrMultipleDoubleColon)
+			}
+			if len(groups) > 7 {
+				return nil, newParseError(input, pos-1, ErrTooManyGroups)
+			}
+			ellipsis = len(groups)
+			pos++
+		}
+	}
+	if ellipsis < 0 && len(groups) != 8 {
+		return nil, newParseError(input, end, ErrTooFewGroups)
+	}
+
+	//expand double colon with zero groups
+	full := make([]uint16, 8)
+	if ellipsis < 0 {
+		copy(full, groups)
+	} else {
+		copy(full, groups[:ellipsis])
+		copy(full[8-len(groups)+ellipsis:], groups[ellipsis:])
+	}
+	addr := Addr{zone: zone}
+	for i, v := range full {
+		if i < 4 {
+			addr.high = addr.high<<16 | uint64(v)
+		} else {
+			addr.low = addr.low<<16 | uint64(v)
+		}
+	}
+	return &addr, nil
+}
+
+func scanDottedQuad(input string, start, end int) (uint32, error) {
+	var ret uint32
+	pos := start
+	for octet := 0; octet < 4; octet++ {
+		if octet > 0 {
+			if pos == end || input[pos] != '.' {
+				return 0, newParseError(input, pos, ErrInvalidIPv4)
+			}
+			pos++
+		}
+		ostart := pos
+		val := uint32(0)
+		for pos < end && input[pos] >= '0' && input[pos] <= '9' {
+			val = val*10 + uint32(input[pos]-'0')
+			if pos-ostart == 3 || val > 255 {
+				return 0, newParseError(input, pos, ErrInvalidIPv4)
+			}
+			pos++
+		}
+		if pos == ostart {
+			return 0, newParseError(input, pos, ErrInvalidIPv4)
+		}
+		ret = ret<<8 | val
+	}
+	if pos != end {
+		return 0, newParseError(input, pos, ErrInvalidIPv4)
+	}
+	return ret, nil
+}
+
+//ParseAddr parses an address in colon separated hex notation as defined by
+//RFC 4291, last 32 bits can be given as ipv4 dotted quad and address can
+//end with %zone; errors are of type *ParseError
+func ParseAddr(s string) (i6 *Addr, e error) {
+	return scanAddr(s, 0, len(s))
+}
+
+//ParsePrefix parses addr/len notation, without /len prefix is a /128; len
+//can also be a netmask (/ffff:ffff::) or a wildcard mask (/::ffff:ffff:ffff)
+//given as an address; errors are of type *ParseError
+func ParsePrefix(s string) (prefix *Prefix, e error) {
+	return parsePrefix(s, (*Addr).maskOrWildcardLength)
+}
+
+//parsePrefix parses the mask with maskLength when it is given as an address
+func parsePrefix(s string, maskLength func(*Addr) (uint, error)) (*Prefix, error) {
+	slash := strings.IndexByte(s, '/')
+	if slash < 0 {
+		i6, err := scanAddr(s, 0, len(s))
+		if err != nil {
+			return nil, err
+		}
+		return &Prefix{*i6, 128, nil}, nil
+	}
+	i6, err := scanAddr(s, 0, slash)
+	if err != nil {
+		return nil, err
+	}
+	mask := uint(0)
+	pos := slash + 1
+	if pos == len(s) {
+		return nil, newParseError(s, slash, ErrInvalidPrefixLength)
+	}
+	if strings.IndexByte(s[pos:], ':') >= 0 {
+		m, err := scanAddr(s, pos, len(s))
+		if err != nil {
+			return nil, err
+		}
+		if len(m.zone) > 0 {
+			return nil, newParseError(s, pos+strings.IndexByte(s[pos:], '%'), ErrInvalidRune)
+		}
+		mask, err = maskLength(m)
+		if err != nil {
+			return nil, newParseError(s, pos, err)
+		}
+		return &Prefix{*i6, mask, nil}, nil
+	}
+	for ; pos < len(s); pos++ {
+		if s[pos] < '0' || s[pos] > '9' {
+			return nil, newParseError(s, pos, ErrInvalidPrefixLength)
+		}
+		mask = mask*10 + uint(s[pos]-'0')
+		if mask > 128 {
+			return nil, newParseError(s, pos, ErrInvalidPrefixLength)
+		}
+	}
+	return &Prefix{*i6, mask, nil}, nil
+}
@@ -0,0 +1,99 @@
+package ipv6calc
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseAddr(t *testing.T) {
+	tests := []struct {
+		in   string
+		high uint64
+		low  uint64
+		zone string
+	}{
+		{"::", 0, 0, ""},
+		{"::1", 0, 1, ""},
+		{"2001:db8::", 0x20010db800000000, 0, ""},
+		{"2001:DB8:0:0:1:2:3:4", 0x20010db800000000, 0x0001000200030004, ""},
+		{"::ffff:192.0.2.1", 0, 0x0000ffffc0000201, ""},
+		{"1:2:3:4:5:6:1.2.3.4", 0x0001000200030004, 0x0005000601020304, ""},
+		{"fe80::1%eth0", 0xfe80000000000000, 1, "eth0"},
+	}
+	for _, tt := range tests {
+		a, err := ParseAddr(tt.in)
+		if err != nil {
+			t.Errorf("ParseAddr(%q) error %v", tt.in, err)
+			continue
+		}
+		if a.High() != tt.high || a.Low() != tt.low || a.Zone() != tt.zone {
+			t.Errorf("ParseAddr(%q) = %016x %016x %q, want %016x %016x %q", tt.in, a.High(), a.Low(), a.Zone(), tt.high, tt.low, tt.zone)
+		}
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		in     string
+		reason error
+		column int
+	}{
+		{"1:2:3:4:5:6:7:8:9", ErrTooManyGroups, 17},
+		{"1:2:3:4::5:6:7:8", ErrTooManyGroups, 16},
+		{"1:2:3:4:5:6:7", ErrTooFewGroups, 14},
+		{"1::2::3", ErrMultipleDoubleColon, 5},
+		{"2001:db8::12345", ErrGroupTooLong, 15},
+		{"2001:db8:::1", ErrEmptyGroup, 11},
+		{":1::", ErrEmptyGroup, 1},
+		{"1::", nil, 0},
+		{"2001:dbg::1", ErrInvalidRune, 8},
+		{"2001:db8::x", ErrInvalidRune, 11},
+		{"2001:ďb8::1", ErrInvalidRune, 6},
+		{"ďď:1::x", ErrInvalidRune, 1},
+		{"2001:db8::ą:x", ErrInvalidRune, 11},
+		{"::ffff:192.0.2.256", ErrInvalidIPv4, 18},
+		{"::ffff:192.0.2", ErrInvalidIPv4, 15},
+		{"::ffff:1.2.3.4.5", ErrInvalidIPv4, 15},
+		{"1:2:3:4:5:6:7:1.2.3.4", ErrTooManyGroups, 15},
+		{"fe80::1%", ErrEmptyZone, 8},
+	}
+	for _, tt := range tests {
+		_, err := ParseAddr(tt.in)
+		if tt.reason == nil {
+			if err != nil {
+				t.Errorf("ParseAddr(%q) error %v", tt.in, err)
+			}
+			continue
+		}
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, tt.reason) || pe.Column != tt.column || pe.Input != tt.in {
+			t.Errorf("ParseAddr(%q) error = %v, want %v at column %v", tt.in, err, tt.reason, tt.column)
+		}
+	}
+}
+
+func TestParsePrefixError(t *testing.T) {
+	tests := []struct {
+		in     string
+		reason error
+		column int
+	}{
+		{"2001:db8::/", ErrInvalidPrefixLength, 11},
+		{"2001:db8::/129", ErrInvalidPrefixLength, 14},
+		{"2001:db8::/4x", ErrInvalidPrefixLength, 13},
+		{"2001:db8::/-1", ErrInvalidPrefixLength, 12},
+		{"2001:db8:::/32", ErrEmptyGroup, 11},
+		{"fe80::%/64", ErrEmptyZone, 7},
+	}
+	for _, tt := range tests {
+		_, err := ParsePrefix(tt.in)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, tt.reason) || pe.Column != tt.column {
+			t.Errorf("ParsePrefix(%q) error = %v, want %v at column %v", tt.in, err, tt.reason, tt.column)
+		}
+	}
+	p, err := ParsePrefix("fe80::1%eth0/64")
+	if err != nil || p.Mask() != 64 || p.addr.Zone() != "eth0" {
+		t.Errorf("ParsePrefix(fe80::1%%eth0/64) = %v, %v", p, err)
+	}
+}
@@ -0,0 +1,93 @@
+package ipv6calc
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+//Range is a continuous block of addresses from first to last, both included
+type Range struct {
+	first Addr
+	last  Addr
+}
+
+//NewRange builds a range, last cannot be lower than first
+func NewRange(first, last Addr) (*Range, error) {
+	if last.less(&first) {
+		return nil, fmt.Errorf("range end %v is before start %v", last, first)
+	}
+	return &Range{first, last}, nil
+}
+
+//ParseRange parses first-last notation (2001:db8::10-2001:db8::ff), errors
+//are of type *ParseError
+func ParseRange(s string) (*Range, error) {
+	dash := strings.IndexByte(s, '-')
+	if dash < 0 {
+		return nil, newParseError(s, len(s), ErrInvalidRange)
+	}
+	first, err := scanAddr(s, 0, dash)
+	if err != nil {
+		return nil, err
+	}
+	last, err := scanAddr(s, dash+1, len(s))
+	if err != nil {
+		return nil, err
+	}
+	if last.less(first) {
+		return nil, newParseError(s, dash+1, ErrRangeOrder)
+	}
+	return &Range{*first, *last}, nil
+}
+
+//First returns the first address of the range
+func (r *Range) First() Addr {
+	return r.first
+}
+
+//Last returns the last address of the range
+func (r *Range) Last() Addr {
+	return r.last
+}
+
+func (r *Range) String() string {
+	return fmt.Sprintf("%v-%v", r.first, r.last)
+}
+
+//Range returns addresses covered by the prefix
+func (p *Prefix) Range() *Range {
+	return &Range{*p.FirstAddressFromSubnet(), *p.LastAddressFromSubnet()}
+}
+
+//trailingZeros counts zero bits at the end of the address, 128 for ::
+func (i6 *Addr) trailingZeros() uint {
+	if i6.low != 0 {
+		return uint(bits.TrailingZeros64(i6.low))
+	}
+	if i6.high != 0 {
+		return uint(64 + bits.TrailingZeros64(i6.high))
+	}
+	return 128
+}
+
+//Prefixes decomposes the range into the minimal list of prefixes, in order
+func (r *Range) Prefixes() []*Prefix {
+	ret := make([]*Prefix, 0)
+	cur := &r.first
+	for cur != nil && !r.last.less(cur) {
+		//largest aligned block starting at cur that does not pass last
+		host := cur.trailingZeros()
+		var blockLast *Addr
+		for ; ; host-- {
+			m, _ := AddrFromMask(128 - host)
+			blockLast = cur.Or(m.Neg())
+			if !r.last.less(blockLast) {
+				break
+			}
+		}
+		ret = append(ret, &Prefix{*cur, 128 - host, nil})
+		cur = blockLast.Inc()
+	}
+	return ret
+}
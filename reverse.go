@@ -0,0 +1,101 @@
+package ipv6calc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+//Reasons reported by ParseError for ip6.arpa names
+var (
+	ErrNotReverseName = errors.New("name does not end with ip6.arpa")
+	ErrInvalidLabel   = errors.New("label is not a single hex digit")
+	ErrTooManyLabels  = errors.New("more than 32 nibble labels")
+)
+
+const reverseSuffix = "ip6.arpa."
+
+//nibble returns k-th 4 bit digit of the address counting from the left
+func (i6 *Addr) nibble(k uint) byte {
+	if k < 16 {
+		return byte(i6.high>>(60-4*k)) & 0xf
+	}
+	return byte(i6.low>>(60-4*(k-16))) & 0xf
+}
+
+//reverseName returns name built from first n nibbles of the address
+func (i6 *Addr) reverseName(n uint) string {
+	var b strings.Builder
+	b.Grow(int(2*n) + len(reverseSuffix))
+	for k := n; k > 0; k-- {
+		b.WriteByte("0123456789abcdef"[i6.nibble(k-1)])
+		b.WriteByte('.')
+	}
+	b.WriteString(reverseSuffix)
+	return b.String()
+}
+
+//ReverseName returns fully qualified nibble-reversed name used for PTR
+//records, like 1.0.0.0...8.b.d.0.1.0.0.2.ip6.arpa.; zone is ignored
+func (i6 *Addr) ReverseName() string {
+	return i6.reverseName(32)
+}
+
+//ReverseZone returns name of the ip6.arpa zone delegated for the prefix,
+//only prefixes with length divisible by 4 have one
+func (p *Prefix) ReverseZone() (string, error) {
+	if p.mask%4 != 0 {
+		return "", fmt.Errorf("/%v is not on a nibble boundary", p.mask)
+	}
+	return p.addr.reverseName(p.mask / 4), nil
+}
+
+//ReverseZones returns ip6.arpa zones that together cover the prefix, that is
+//the zone of the prefix itself or zones of all its children of the next
+//nibble aligned length
+func (p *Prefix) ReverseZones() []string {
+	it, _ := p.Split((p.mask + 3) / 4 * 4)
+	ret := make([]string, 0, 1<<((4-p.mask%4)%4))
+	for c := it.Next(); c != nil; c = it.Next() {
+		z, _ := c.ReverseZone()
+		ret = append(ret, z)
+	}
+	return ret
+}
+
+//ParseReverseName parses an ip6.arpa name back into a prefix of length four
+//times number of nibble labels, a full name gives a /128; trailing dot and
+//letter case do not matter, errors are of type *ParseError
+func ParseReverseName(s string) (*Prefix, error) {
+	name := strings.TrimSuffix(s, ".")
+	if !strings.HasSuffix(strings.ToLower(name), "ip6.arpa") {
+		return nil, newParseError(s, len(name), ErrNotReverseName)
+	}
+	name = name[:len(name)-len("ip6.arpa")]
+	if len(name) == 0 {
+		return &Prefix{Addr{}, 0, nil}, nil
+	}
+	if name[len(name)-1] != '.' {
+		return nil, newParseError(s, len(name), ErrNotReverseName)
+	}
+	labels := strings.Split(name[:len(name)-1], ".")
+	if len(labels) > 32 {
+		return nil, newParseError(s, 0, ErrTooManyLabels)
+	}
+	var i6 Addr
+	pos := 0
+	for i, l := range labels {
+		if len(l) != 1 || !checkHexChar(l[0]) {
+			return nil, newParseError(s, pos, ErrInvalidLabel)
+		}
+		pos += len(l) + 1
+		v := hexToInt(l[0])
+		k := uint(len(labels) - 1 - i)
+		if k < 16 {
+			i6.high |= v << (60 - 4*k)
+		} else {
+			i6.low |= v << (60 - 4*(k-16))
+		}
+	}
+	return &Prefix{i6, uint(4 * len(labels)), nil}, nil
+}
@@ -0,0 +1,91 @@
+package ipv6calc
+
+import (
+	"errors"
+	"math/rand"
+	"testing"
+)
+
+func TestReverseName(t *testing.T) {
+	a := mustAddr(t, "2001:db8::567:89ab")
+	want := "b.a.9.8.7.6.5.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa."
+	if got := a.ReverseName(); got != want {
+		t.Errorf("ReverseName() = %v, want %v", got, want)
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		a := Addr{high: r.Uint64(), low: r.Uint64()}
+		p, err := ParseReverseName(a.ReverseName())
+		if err != nil || p.mask != 128 || p.addr != a {
+			t.Fatalf("ParseReverseName(%v) = %v, %v, want %v/128", a.ReverseName(), p, err, a.CanonicalString())
+		}
+		q := (&Prefix{a, uint(r.Intn(33)) * 4, nil}).MakeSubnetAddress()
+		z, err := q.ReverseZone()
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err = ParseReverseName(z)
+		if err != nil || p.CanonicalString() != q.CanonicalString() {
+			t.Fatalf("ParseReverseName(%v) = %v, %v, want %v", z, p, err, q.CanonicalString())
+		}
+	}
+}
+
+func TestReverseZones(t *testing.T) {
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"::/0", []string{"ip6.arpa."}},
+		{"2001:db8::/32", []string{"8.b.d.0.1.0.0.2.ip6.arpa."}},
+		{"2001:db8::/30", []string{"8.b.d.0.1.0.0.2.ip6.arpa.", "9.b.d.0.1.0.0.2.ip6.arpa.", "a.b.d.0.1.0.0.2.ip6.arpa.", "b.b.d.0.1.0.0.2.ip6.arpa."}},
+		{"2001:db8::/31", []string{"8.b.d.0.1.0.0.2.ip6.arpa.", "9.b.d.0.1.0.0.2.ip6.arpa."}},
+		{"8000::/2", []string{"8.ip6.arpa.", "9.ip6.arpa.", "a.ip6.arpa.", "b.ip6.arpa."}},
+	}
+	for _, tt := range tests {
+		p := mustPrefix(t, tt.prefix)
+		if got := p.ReverseZones(); !equalStrings(got, tt.want) {
+			t.Errorf("ReverseZones(%v) = %v, want %v", tt.prefix, got, tt.want)
+		}
+		if _, err := p.ReverseZone(); (err == nil) != (p.mask%4 == 0) {
+			t.Errorf("ReverseZone(%v) error = %v", tt.prefix, err)
+		}
+	}
+}
+
+func TestParseReverseName(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"ip6.arpa", "::/0"},
+		{"ip6.arpa.", "::/0"},
+		{"8.B.D.0.1.0.0.2.IP6.ARPA", "2001:db8::/32"},
+		{"1.8.b.d.0.1.0.0.2.ip6.arpa.", "2001:db8:1000::/36"},
+	}
+	for _, tt := range tests {
+		p, err := ParseReverseName(tt.in)
+		if err != nil || p.CanonicalString() != tt.want {
+			t.Errorf("ParseReverseName(%q) = %v, %v, want %v", tt.in, p, err, tt.want)
+		}
+	}
+	bad := []struct {
+		in     string
+		reason error
+		column int
+	}{
+		{"8.b.d.0.1.0.0.2.in-addr.arpa", ErrNotReverseName, 29},
+		{"1ip6.arpa", ErrNotReverseName, 2},
+		{"8.b.g.0.ip6.arpa", ErrInvalidLabel, 5},
+		{"8.bd.0.ip6.arpa", ErrInvalidLabel, 3},
+		{"8..0.ip6.arpa", ErrInvalidLabel, 3},
+		{"0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.ip6.arpa", ErrTooManyLabels, 1},
+	}
+	for _, tt := range bad {
+		_, err := ParseReverseName(tt.in)
+		var pe *ParseError
+		if !errors.As(err, &pe) || !errors.Is(err, tt.reason) || pe.Column != tt.column {
+			t.Errorf("ParseReverseName(%q) error = %v, want %v at column %v", tt.in, err, tt.reason, tt.column)
+		}
+	}
+}
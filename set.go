@@ -0,0 +1,174 @@
+package ipv6calc
+
+import (
+	"math/big"
+	"sort"
+	"strings"
+)
+
+//Set is an immutable set of addresses kept as sorted, non overlapping and
+//non adjacent ranges; operations return new sets
+type Set struct {
+	ranges []Range
+}
+
+//SetFromPrefixes builds set of addresses covered by ps
+func SetFromPrefixes(ps ...*Prefix) *Set {
+	rs := make([]Range, len(ps))
+	for i, p := range ps {
+		rs[i] = *p.Range()
+	}
+	return newSet(rs)
+}
+
+//SetFromRanges builds set of addresses covered by rs
+func SetFromRanges(rs ...*Range) *Set {
+	c := make([]Range, len(rs))
+	for i, r := range rs {
+		c[i] = *r
+	}
+	return newSet(c)
+}
+
+//newSet sorts rs and merges overlapping and adjacent ranges, rs is reused
+func newSet(rs []Range) *Set {
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].first.less(&rs[j].first)
+	})
+	ret := make([]Range, 0, len(rs))
+	for _, r := range rs {
+		if len(ret) > 0 {
+			top := &ret[len(ret)-1]
+			next := top.last.Inc()
+			//nil means top reaches the end of address space
+			if next == nil || !next.less(&r.first) {
+				if top.last.less(&r.last) {
+					top.last = r.last
+				}
+				continue
+			}
+		}
+		ret = append(ret, r)
+	}
+	return &Set{ret}
+}
+
+//Union returns addresses that are in s or o
+func (s *Set) Union(o *Set) *Set {
+	rs := make([]Range, 0, len(s.ranges)+len(o.ranges))
+	rs = append(rs, s.ranges...)
+	rs = append(rs, o.ranges...)
+	return newSet(rs)
+}
+
+//Intersection returns addresses that are both in s and o
+func (s *Set) Intersection(o *Set) *Set {
+	ret := make([]Range, 0)
+	i, j := 0, 0
+	for i < len(s.ranges) && j < len(o.ranges) {
+		a, b := &s.ranges[i], &o.ranges[j]
+		lo, hi := a.first, a.last
+		if lo.less(&b.first) {
+			lo = b.first
+		}
+		if b.last.less(&hi) {
+			hi = b.last
+		}
+		if !hi.less(&lo) {
+			ret = append(ret, Range{lo, hi})
+		}
+		if a.last.less(&b.last) {
+			i++
+		} else {
+			j++
+		}
+	}
+	return &Set{ret}
+}
+
+//Difference returns addresses of s that are not in o
+func (s *Set) Difference(o *Set) *Set {
+	ret := make([]Range, 0)
+	j := 0
+	for _, r := range s.ranges {
+		cur := &r.first
+		//skip ranges of o ending before r
+		for j < len(o.ranges) && o.ranges[j].last.less(cur) {
+			j++
+		}
+		for k := j; cur != nil && k < len(o.ranges) && !r.last.less(&o.ranges[k].first); k++ {
+			b := &o.ranges[k]
+			if cur.less(&b.first) {
+				ret = append(ret, Range{*cur, *b.first.Dec()})
+			}
+			cur = b.last.Inc()
+		}
+		if cur != nil && !r.last.less(cur) {
+			ret = append(ret, Range{*cur, r.last})
+		}
+	}
+	return &Set{ret}
+}
+
+//Contains tells if address is in the set
+func (s *Set) Contains(a *Addr) bool {
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return !s.ranges[i].last.less(a)
+	})
+	return i < len(s.ranges) && !a.less(&s.ranges[i].first)
+}
+
+//ContainsPrefix tells if all addresses of p are in the set
+func (s *Set) ContainsPrefix(p *Prefix) bool {
+	first := p.FirstAddressFromSubnet()
+	i := sort.Search(len(s.ranges), func(i int) bool {
+		return !s.ranges[i].last.less(first)
+	})
+	return i < len(s.ranges) && !first.less(&s.ranges[i].first) && !s.ranges[i].last.less(p.LastAddressFromSubnet())
+}
+
+//IsEmpty tells if set has no addresses
+func (s *Set) IsEmpty() bool {
+	return len(s.ranges) == 0
+}
+
+//Size returns number of addresses in the set
+func (s *Set) Size() *big.Int {
+	ret := new(big.Int)
+	one := big.NewInt(1)
+	for _, r := range s.ranges {
+		n := r.last.BigInt()
+		n.Sub(n, r.first.BigInt())
+		n.Add(n, one)
+		ret.Add(ret, n)
+	}
+	return ret
+}
+
+//Ranges returns the set as sorted list of ranges
+func (s *Set) Ranges() []*Range {
+	ret := make([]*Range, len(s.ranges))
+	for i := range s.ranges {
+		r := s.ranges[i]
+		ret[i] = &r
+	}
+	return ret
+}
+
+//Prefixes returns the set as the minimal sorted list of prefixes
+func (s *Set) Prefixes() []*Prefix {
+	ret := make([]*Prefix, 0, len(s.ranges))
+	for i := range s.ranges {
+		ret = append(ret, s.ranges[i].Prefixes()...)
+	}
+	return ret
+}
+
+func (s *Set) String() string {
+	ps := s.Prefixes()
+	ss := make([]string, len(ps))
+	for i, p := range ps {
+		ss[i] = p.String()
+	}
+	return "{" + strings.Join(ss, ", ") + "}"
+}
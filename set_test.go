@@ -0,0 +1,142 @@
+package ipv6calc
+
+import (
+	"math/big"
+	"testing"
+)
+
+func mustRange(t testing.TB, s string) *Range {
+	t.Helper()
+	r, err := ParseRange(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestSetFrom(t *testing.T) {
+	tests := []struct {
+		ranges []string
+		want   []string
+	}{
+		//adjacent ranges merge
+		{[]string{"2001:db8::-2001:db8::ff", "2001:db8::100-2001:db8::1ff"}, []string{"2001:db8::/119"}},
+		//overlapping and unsorted
+		{[]string{"2001:db8::80-2001:db8::ff", "2001:db8::-2001:db8::8f"}, []string{"2001:db8::/120"}},
+		{[]string{"2001:db8::-2001:db8::fe", "2001:db8::100-2001:db8::1ff"}, []string{"2001:db8::/121", "2001:db8::80/122", "2001:db8::c0/123", "2001:db8::e0/124", "2001:db8::f0/125", "2001:db8::f8/126", "2001:db8::fc/127", "2001:db8::fe/128", "2001:db8::100/120"}},
+		//range ending at the last address followed by another one
+		{[]string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff", "ffff:ffff:ffff:ffff:ffff:ffff:ffff:fff0-ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"}, []string{"ffff:ffff:ffff:ffff:ffff:ffff:ffff:ff00/120"}},
+	}
+	for _, tt := range tests {
+		rs := make([]*Range, len(tt.ranges))
+		for i, s := range tt.ranges {
+			rs[i] = mustRange(t, s)
+		}
+		if got := prefixStrings(SetFromRanges(rs...).Prefixes()); !equalStrings(got, tt.want) {
+			t.Errorf("SetFromRanges(%v) = %v, want %v", tt.ranges, got, tt.want)
+		}
+	}
+	s := SetFromPrefixes(mustPrefixes(t, "2001:db8::/33", "2001:db8:8000::/33")...)
+	if len(s.Ranges()) != 1 || s.String() != "{2001:db8::/32}" {
+		t.Errorf("adjacent prefixes not merged: %v", s)
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	all := "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff/128"
+	tests := []struct {
+		a, b                []string
+		union, inter, aMinB []string
+	}{
+		{
+			[]string{"2001:db8::/32"}, []string{"2001:db8:1::/48", "2001:db9::/32"},
+			[]string{"2001:db8::/31"}, []string{"2001:db8:1::/48"}, []string{"2001:db8::/48", "2001:db8:2::/47", "2001:db8:4::/46", "2001:db8:8::/45", "2001:db8:10::/44", "2001:db8:20::/43", "2001:db8:40::/42", "2001:db8:80::/41", "2001:db8:100::/40", "2001:db8:200::/39", "2001:db8:400::/38", "2001:db8:800::/37", "2001:db8:1000::/36", "2001:db8:2000::/35", "2001:db8:4000::/34", "2001:db8:8000::/33"},
+		},
+		{
+			[]string{"2001:db8::/48", "2001:db8:2::/48"}, []string{"2001:db8:1::/48"},
+			[]string{"2001:db8::/47", "2001:db8:2::/48"}, []string{}, []string{"2001:db8::/48", "2001:db8:2::/48"},
+		},
+		{
+			[]string{"::/0"}, []string{"::/1"},
+			[]string{"::/0"}, []string{"::/1"}, []string{"8000::/1"},
+		},
+		//ranges touching the last address, where Inc() returns nil
+		{
+			[]string{"ffff::/16"}, []string{all},
+			[]string{"ffff::/16"}, []string{all}, nil,
+		},
+		{
+			[]string{all}, []string{"ffff::/16"},
+			[]string{"ffff::/16"}, []string{all}, []string{},
+		},
+		{
+			[]string{}, []string{"2001:db8::/32"},
+			[]string{"2001:db8::/32"}, []string{}, []string{},
+		},
+	}
+	for _, tt := range tests {
+		a := SetFromPrefixes(mustPrefixes(t, tt.a...)...)
+		b := SetFromPrefixes(mustPrefixes(t, tt.b...)...)
+		if got := prefixStrings(a.Union(b).Prefixes()); !equalStrings(got, tt.union) {
+			t.Errorf("%v union %v = %v, want %v", tt.a, tt.b, got, tt.union)
+		}
+		if got := prefixStrings(a.Intersection(b).Prefixes()); !equalStrings(got, tt.inter) {
+			t.Errorf("%v intersect %v = %v, want %v", tt.a, tt.b, got, tt.inter)
+		}
+		diff := a.Difference(b)
+		if tt.aMinB == nil {
+			//everything up to the last address
+			rs := diff.Ranges()
+			if len(rs) != 1 || rs[0].first.CanonicalString() != "ffff::" || rs[0].last.CanonicalString() != "ffff:ffff:ffff:ffff:ffff:ffff:ffff:fffe" {
+				t.Errorf("%v diff %v = %v", tt.a, tt.b, rs)
+			}
+		} else if got := prefixStrings(diff.Prefixes()); !equalStrings(got, tt.aMinB) {
+			t.Errorf("%v diff %v = %v, want %v", tt.a, tt.b, got, tt.aMinB)
+		}
+		//a = (a - b) + (a & b)
+		if !equalStrings(prefixStrings(diff.Union(a.Intersection(b)).Prefixes()), prefixStrings(a.Prefixes())) {
+			t.Errorf("%v diff %v does not add up with intersection", tt.a, tt.b)
+		}
+	}
+}
+
+func TestSetQueries(t *testing.T) {
+	s := SetFromPrefixes(mustPrefixes(t, "2001:db8::/48", "2001:db8:1::/48", "2001:db8:3::/64")...)
+	size := new(big.Int).Lsh(big.NewInt(1), 81)
+	size.Add(size, new(big.Int).Lsh(big.NewInt(1), 64))
+	if s.Size().Cmp(size) != 0 {
+		t.Errorf("Size() = %v, want %v", s.Size(), size)
+	}
+	tests := []struct {
+		prefix string
+		want   bool
+	}{
+		{"2001:db8::/47", true},
+		{"2001:db8:1:ffff::/64", true},
+		{"2001:db8::/46", false},
+		{"2001:db8:2::1/128", false},
+		{"2001:db8:3::/64", true},
+		{"2001:db8:3::/63", false},
+		{"2001:db8:0:ffff:ffff:ffff:ffff:ffff/128", true},
+	}
+	for _, tt := range tests {
+		p := mustPrefix(t, tt.prefix)
+		if got := s.ContainsPrefix(p); got != tt.want {
+			t.Errorf("ContainsPrefix(%v) = %v, want %v", tt.prefix, got, tt.want)
+		}
+		if p.Mask() == 128 {
+			a := p.Addr()
+			if got := s.Contains(&a); got != tt.want {
+				t.Errorf("Contains(%v) = %v, want %v", tt.prefix, got, tt.want)
+			}
+		}
+	}
+	empty := SetFromPrefixes()
+	if !empty.IsEmpty() || empty.Size().Sign() != 0 || empty.Contains(mustAddr(t, "::")) {
+		t.Errorf("empty set is not empty")
+	}
+	full := SetFromPrefixes(mustPrefix(t, "::/0"))
+	if full.Size().Cmp(new(big.Int).Lsh(big.NewInt(1), 128)) != 0 {
+		t.Errorf("Size() of ::/0 = %v", full.Size())
+	}
+}
@@ -0,0 +1,66 @@
+package ipv6calc
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+//Pow2 is a count equal to 2 to the power of its value, sizes of prefixes are
+//always such counts
+type Pow2 uint
+
+//BigInt returns exact value
+func (n Pow2) BigInt() *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), uint(n))
+}
+
+//String returns power notation, like 2^80
+func (n Pow2) String() string {
+	return fmt.Sprintf("2^%v", uint(n))
+}
+
+//Decimal returns exact decimal value, like 1208925819614629174706176
+func (n Pow2) Decimal() string {
+	return n.BigInt().String()
+}
+
+//Sci returns rounded value in scientific notation, like 1.2e24; values below
+//a million are exact
+func (n Pow2) Sci() string {
+	if n < 20 {
+		return n.Decimal()
+	}
+	f := new(big.Float).SetInt(n.BigInt())
+	mant, exp, _ := strings.Cut(f.Text('e', 1), "e")
+	return mant + "e" + strings.TrimLeft(exp, "+0")
+}
+
+//Human returns all three forms, like 2^80 = 1208925819614629174706176 (1.2e24)
+func (n Pow2) Human() string {
+	if n < 20 {
+		return fmt.Sprintf("%v = %v", n, n.Decimal())
+	}
+	return fmt.Sprintf("%v = %v (%v)", n, n.Decimal(), n.Sci())
+}
+
+//Size returns number of addresses in the prefix
+func (p *Prefix) Size() Pow2 {
+	return Pow2(128 - p.mask)
+}
+
+//NumAddresses returns number of addresses in the prefix
+func (p *Prefix) NumAddresses() *big.Int {
+	return p.Size().BigInt()
+}
+
+//NumSubnets returns how many prefixes of length newMask fit in p
+func (p *Prefix) NumSubnets(newMask uint) (Pow2, error) {
+	if newMask > 128 {
+		return 0, fmt.Errorf("invalid prefix length %v", newMask)
+	}
+	if newMask < p.mask {
+		return 0, fmt.Errorf("/%v does not fit in /%v", newMask, p.mask)
+	}
+	return Pow2(newMask - p.mask), nil
+}
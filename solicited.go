@@ -0,0 +1,63 @@
+package ipv6calc
+
+import (
+	"errors"
+)
+
+//solicitedNodePrefix is ff02::1:ff00:0/104
+var solicitedNodePrefix = Prefix{Addr{high: 0xff02000000000000, low: 0x00000001ff000000}, 104, nil}
+
+//solicitedNodeMask keeps the last 24 bits copied from the unicast address
+var solicitedNodeMask = Addr{low: 0xffffff}
+
+//SolicitedNode returns solicited-node multicast address ff02::1:ffXX:XXXX of
+//the address as defined by RFC 4291
+func (i6 *Addr) SolicitedNode() *Addr {
+	a := solicitedNodePrefix.addr.Or(i6.And(&solicitedNodeMask))
+	a.zone = i6.zone
+	return a
+}
+
+//IsSolicitedNode checks if address is in ff02::1:ff00:0/104
+func (i6 *Addr) IsSolicitedNode() bool {
+	return solicitedNodePrefix.Contains(i6)
+}
+
+//SolicitedNodeIterator returns addresses of a prefix which have the same
+//solicited-node multicast address
+type SolicitedNodeIterator struct {
+	it     *SubnetIterator
+	p      *Prefix
+	suffix *Addr
+}
+
+//SolicitedNodeMembers returns iterator over addresses in p which map to
+//solicited-node address sn, there are 2^(104-len) of them for prefixes
+//shorter than /104 and at most one for longer ones
+func (p *Prefix) SolicitedNodeMembers(sn *Addr) (*SolicitedNodeIterator, error) {
+	if !sn.IsSolicitedNode() {
+		return nil, errors.New("not a solicited-node multicast address")
+	}
+	mask := p.mask
+	if mask < solicitedNodePrefix.mask {
+		mask = solicitedNodePrefix.mask
+	}
+	it, err := p.Split(mask)
+	if err != nil {
+		return nil, err
+	}
+	return &SolicitedNodeIterator{it, p, sn.And(&solicitedNodeMask)}, nil
+}
+
+//Next returns next matching address or nil when all were returned
+func (s *SolicitedNodeIterator) Next() *Addr {
+	for c := s.it.Next(); c != nil; c = s.it.Next() {
+		//children longer than /104 have some of the last 24 bits set
+		a := c.FirstAddressFromSubnet().And(solicitedNodeMask.Neg()).Or(s.suffix)
+		if s.p.Contains(a) && a.And(&solicitedNodeMask).Cmp(s.suffix) == 0 {
+			a.zone = s.p.addr.zone
+			return a
+		}
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package ipv6calc
+
+import "testing"
+
+func TestSolicitedNode(t *testing.T) {
+	a := mustAddr(t, "2001:db8::1234:5678:9abc")
+	if got := a.SolicitedNode().CanonicalString(); got != "ff02::1:ff78:9abc" {
+		t.Errorf("SolicitedNode() = %v, want ff02::1:ff78:9abc", got)
+	}
+}
+
+func TestSolicitedNodeMembers(t *testing.T) {
+	tests := []struct {
+		prefix string
+		sn     string
+		want   []string
+	}{
+		{"2001:db8::/102", "ff02::1:ff00:5", []string{"2001:db8::5", "2001:db8::100:5", "2001:db8::200:5", "2001:db8::300:5"}},
+		{"2001:db8::/104", "ff02::1:ff00:5", []string{"2001:db8::5"}},
+		{"2001:db8::10/124", "ff02::1:ff00:15", []string{"2001:db8::15"}},
+		{"2001:db8::10/124", "ff02::1:ff00:5", []string{}},
+		{"2001:db8::10/128", "ff02::1:ff00:10", []string{"2001:db8::10"}},
+		{"2001:db8::10/128", "ff02::1:ff00:11", []string{}},
+	}
+	for _, tt := range tests {
+		it, err := mustPrefix(t, tt.prefix).SolicitedNodeMembers(mustAddr(t, tt.sn))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, 0)
+		for a := it.Next(); a != nil; a = it.Next() {
+			if a.SolicitedNode().CanonicalString() != tt.sn {
+				t.Errorf("%v maps to %v, not %v", a.CanonicalString(), a.SolicitedNode().CanonicalString(), tt.sn)
+			}
+			got = append(got, a.CanonicalString())
+		}
+		if !equalStrings(got, tt.want) {
+			t.Errorf("SolicitedNodeMembers(%v, %v) = %v, want %v", tt.prefix, tt.sn, got, tt.want)
+		}
+	}
+	if _, err := mustPrefix(t, "2001:db8::/64").SolicitedNodeMembers(mustAddr(t, "ff02::1")); err == nil {
+		t.Errorf("SolicitedNodeMembers accepted ff02::1")
+	}
+}
@@ -0,0 +1,105 @@
+package ipv6calc
+
+import (
+	"errors"
+	"fmt"
+)
+
+//less compares addresses as 128 bit numbers, zones are ignored
+func (i6 *Addr) less(i *Addr) bool {
+	if i6.high != i.high {
+		return i6.high < i.high
+	}
+	return i6.low < i.low
+}
+
+//addShifted adds n<<shift to the address, returns nil on overflow
+func (i6 *Addr) addShifted(n uint64, shift uint) *Addr {
+	if n == 0 {
+		return &Addr{i6.high, i6.low, i6.zone}
+	}
+	if shift >= 128 {
+		return nil
+	}
+	var h, l uint64
+	if shift >= 64 {
+		if shift > 64 && n>>(128-shift) != 0 {
+			return nil
+		}
+		h = n << (shift - 64)
+	} else {
+		l = n << shift
+		if shift > 0 {
+			h = n >> (64 - shift)
+		}
+	}
+	nl := i6.low + l
+	if nl < i6.low {
+		h++
+		if h == 0 {
+			return nil
+		}
+	}
+	nh := i6.high + h
+	if nh < i6.high {
+		return nil
+	}
+	return &Addr{nh, nl, i6.zone}
+}
+
+//SubnetIterator walks child prefixes of a prefix in order without keeping
+//them in memory, Next returns nil after the last child
+type SubnetIterator struct {
+	cur  *Addr
+	last Addr
+	mask uint
+}
+
+//Split returns iterator over all children of p with length newMask, a /32
+//split into /64s gives 2^32 prefixes so they are generated lazily
+func (p *Prefix) Split(newMask uint) (*SubnetIterator, error) {
+	if newMask > 128 {
+		return nil, errors.New("mask is too long")
+	}
+	if newMask < p.mask {
+		return nil, fmt.Errorf("cannot split /%v into shorter /%v prefixes", p.mask, newMask)
+	}
+	return &SubnetIterator{p.FirstAddressFromSubnet(), *p.LastAddressFromSubnet(), newMask}, nil
+}
+
+//Next returns the next child prefix or nil when all were returned
+func (it *SubnetIterator) Next() *Prefix {
+	if it.cur == nil {
+		return nil
+	}
+	p := &Prefix{*it.cur, it.mask, nil}
+	it.Skip(1)
+	return p
+}
+
+//Skip moves over n child prefixes without returning them
+func (it *SubnetIterator) Skip(n uint64) {
+	if it.cur == nil {
+		return
+	}
+	next := it.cur.addShifted(n, 128-it.mask)
+	if next == nil || it.last.less(next) {
+		it.cur = nil
+		return
+	}
+	it.cur = next
+}
+
+//Child returns n-th (counting from 0) child prefix of p with length newMask
+func (p *Prefix) Child(newMask uint, n uint64) (*Prefix, error) {
+	it, err := p.Split(newMask)
+	if err != nil {
+		return nil, err
+	}
+	it.Skip(n)
+	c := it.Next()
+	if c == nil {
+		return nil, fmt.Errorf("/%v has no child /%v number %v", p.mask, newMask, n)
+	}
+	return c, nil
+}
@@ -0,0 +1,146 @@
+package ipv6calc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+	"net"
+)
+
+//stableRetries is how many times generation is retried with incremented DAD
+//counter when the result is a reserved interface ID; it is also the Linux
+//idgen_retries default, where it limits the DAD counter itself
+const stableRetries = 3
+
+//reservedInterfaceID checks the interface ID against RFC 5453 reserved IDs:
+//subnet-router anycast, proxy mobile IPv6 and reserved subnet anycast
+func reservedInterfaceID(id uint64) bool {
+	if id == 0 {
+		return true
+	}
+	if id>>32 == 0x02005eff && id&0xff000000 == 0xfe000000 {
+		return true
+	}
+	if id>>32 == 0xfdffffff && id&0xffffff80 == 0xffffff80 {
+		return true
+	}
+	return false
+}
+
+//linuxReservedInterfaceID is reservedInterfaceID as Linux
+//ipv6_reserved_interfaceid implements it, its proxy mobile IPv6 check also
+//matches 02005eff:ff000000-02005eff:ffffffff
+func linuxReservedInterfaceID(id uint64) bool {
+	if id>>32 == 0x02005eff && id&0xfe000000 == 0xfe000000 {
+		return true
+	}
+	return reservedInterfaceID(id)
+}
+
+//stableAddress retries f with incremented DAD counter until it returns an
+//interface ID that reserved does not match, the counter is not incremented
+//past maxCounter
+func (p *Prefix) stableAddress(dadCounter, maxCounter uint8, reserved func(id uint64) bool, f func(dad uint8) uint64) (*Addr, error) {
+	if p.mask != 64 {
+		return nil, fmt.Errorf("stable addresses need a /64 prefix, not /%v", p.mask)
+	}
+	for {
+		id := f(dadCounter)
+		if !reserved(id) {
+			return &Addr{p.addr.high, id, p.addr.zone}, nil
+		}
+		if dadCounter >= maxCounter {
+			return nil, errors.New("only reserved interface IDs were generated")
+		}
+		dadCounter++
+	}
+}
+
+//StablePrivacy returns RFC 7217 semantically opaque address in a /64 prefix,
+//interface ID is the first 64 bits of SHA-256 of prefix, netIface (like an
+//interface name), networkID (like SSID, may be empty), DAD counter and secret;
+//reserved interface IDs are retried with up to 3 next DAD counter values
+func (p *Prefix) StablePrivacy(netIface, networkID []byte, dadCounter uint8, secret []byte) (*Addr, error) {
+	maxCounter := uint8(255)
+	if dadCounter < 255-stableRetries {
+		maxCounter = dadCounter + stableRetries
+	}
+	return p.stableAddress(dadCounter, maxCounter, reservedInterfaceID, func(dad uint8) uint64 {
+		h := sha256.New()
+		var prefix [8]byte
+		binary.BigEndian.PutUint64(prefix[:], p.addr.high)
+		h.Write(prefix[:])
+		h.Write(netIface)
+		h.Write(networkID)
+		h.Write([]byte{dad})
+		h.Write(secret)
+		return binary.BigEndian.Uint64(h.Sum(nil))
+	})
+}
+
+//LinuxStablePrivacy returns the address Linux generates with
+//addr_gen_mode=stable_privacy (net.ipv6.conf.*.stable_secret set). Linux
+//follows RFC 7217 with the permanent hardware address as the interface and
+//no network ID; hash is a single SHA-1 block over secret, prefix, hardware
+//address padded to 32 bytes and DAD counter, words of the digest are stored
+//in host order, which is little endian here as on x86 and arm64 hosts.
+//Devices without a permanent address, like veth, hash zeros instead of MAC.
+//As in Linux, reserved interface IDs are retried only while the DAD counter
+//stays within idgen_retries (3), whatever counter the generation started with,
+//and IDs are checked with the kernel's wider proxy mobile IPv6 range
+func (p *Prefix) LinuxStablePrivacy(hwaddr net.HardwareAddr, dadCounter uint8, secret *Addr) (*Addr, error) {
+	if len(hwaddr) > 32 {
+		return nil, fmt.Errorf("hardware address longer than 32 bytes")
+	}
+	maxCounter := uint8(stableRetries)
+	if dadCounter > maxCounter {
+		maxCounter = dadCounter
+	}
+	return p.stableAddress(dadCounter, maxCounter, linuxReservedInterfaceID, func(dad uint8) uint64 {
+		var block [64]byte
+		binary.BigEndian.PutUint64(block[0:], secret.high)
+		binary.BigEndian.PutUint64(block[8:], secret.low)
+		binary.BigEndian.PutUint64(block[16:], p.addr.high)
+		copy(block[24:56], hwaddr)
+		block[56] = dad
+		digest := sha1Block(&block)
+		return uint64(bits.ReverseBytes32(digest[0]))<<32 | uint64(bits.ReverseBytes32(digest[1]))
+	})
+}
+
+//sha1Block runs the SHA-1 compression function on one block from the initial
+//state, without padding and length as Linux sha1_transform does
+func sha1Block(block *[64]byte) [5]uint32 {
+	h := [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+	var w [80]uint32
+	for i := 0; i < 16; i++ {
+		w[i] = binary.BigEndian.Uint32(block[4*i:])
+	}
+	for i := 16; i < 80; i++ {
+		w[i] = bits.RotateLeft32(w[i-3]^w[i-8]^w[i-14]^w[i-16], 1)
+	}
+	a, b, c, d, e := h[0], h[1], h[2], h[3], h[4]
+	for i := 0; i < 80; i++ {
+		var f, k uint32
+		switch {
+		case i < 20:
+			f, k = b&c|^b&d, 0x5a827999
+		case i < 40:
+			f, k = b^c^d, 0x6ed9eba1
+		case i < 60:
+			f, k = b&c|b&d|c&d, 0x8f1bbcdc
+		default:
+			f, k = b^c^d, 0xca62c1d6
+		}
+		t := bits.RotateLeft32(a, 5) + f + e + k + w[i]
+		e, d, c, b, a = d, c, bits.RotateLeft32(b, 30), a, t
+	}
+	h[0] += a
+	h[1] += b
+	h[2] += c
+	h[3] += d
+	h[4] += e
+	return h
+}
@@ -0,0 +1,69 @@
+package ipv6calc
+
+import (
+	"net"
+	"testing"
+)
+
+func TestLinuxStablePrivacy(t *testing.T) {
+	//generated by Linux 6.18 on veth pair (no permanent address) with
+	//stable_secret 2001:db8:1:2:3:4:5:6, second one after failed DAD
+	p := mustPrefix(t, "fe80::/64")
+	secret := mustAddr(t, "2001:db8:1:2:3:4:5:6")
+	for dad, want := range []string{"fe80::ff05:eb87:4e94:b3ad", "fe80::aef7:1885:4039:9db"} {
+		a, err := p.LinuxStablePrivacy(make(net.HardwareAddr, 6), uint8(dad), secret)
+		if err != nil || a.CanonicalString() != want {
+			t.Errorf("LinuxStablePrivacy(dad %v) = %v, %v, want %v", dad, a, err, want)
+		}
+	}
+}
+
+func TestReservedInterfaceID(t *testing.T) {
+	tests := []struct {
+		id       uint64
+		reserved bool
+		linux    bool
+	}{
+		{0, true, true},
+		{0x02005efffe000000, true, true},
+		{0x02005efffeffffff, true, true},
+		//outside RFC 5453 range, Linux masks the proxy mobile IPv6 ID with fe000000
+		{0x02005effff000000, false, true},
+		{0x02005effffffffff, false, true},
+		{0x02005efffd000000, false, false},
+		{0xfdffffffffffff80, true, true},
+		{0xfdffffffffffffff, true, true},
+		{0xfdffffffffffff7f, false, false},
+		{1, false, false},
+	}
+	for _, tt := range tests {
+		if got := reservedInterfaceID(tt.id); got != tt.reserved {
+			t.Errorf("reservedInterfaceID(%016x) = %v, want %v", tt.id, got, tt.reserved)
+		}
+		if got := linuxReservedInterfaceID(tt.id); got != tt.linux {
+			t.Errorf("linuxReservedInterfaceID(%016x) = %v, want %v", tt.id, got, tt.linux)
+		}
+	}
+}
+
+func TestStableAddressRetries(t *testing.T) {
+	p := mustPrefix(t, "2001:db8::/64")
+	tests := []struct {
+		start, max uint8
+		tried      []uint8
+	}{
+		{0, 3, []uint8{0, 1, 2, 3}},
+		{2, 3, []uint8{2, 3}},
+		{5, 5, []uint8{5}},
+	}
+	for _, tt := range tests {
+		tried := make([]uint8, 0)
+		_, err := p.stableAddress(tt.start, tt.max, reservedInterfaceID, func(dad uint8) uint64 {
+			tried = append(tried, dad)
+			return 0
+		})
+		if err == nil || string(tried) != string(tt.tried) {
+			t.Errorf("stableAddress(%v, %v) tried %v, want %v", tt.start, tt.max, tried, tt.tried)
+		}
+	}
+}
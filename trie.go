@@ -0,0 +1,260 @@
+package ipv6calc
+
+import "math/bits"
+
+//trieNode is a node of path compressed binary trie, nodes without value
+//(set == false) only join two subtrees
+type trieNode[V any] struct {
+	high  uint64
+	low   uint64
+	mask  uint
+	set   bool
+	value V
+	child [2]*trieNode[V]
+}
+
+//Trie maps prefixes to values of any type and answers longest prefix match
+//and covering/covered queries. It is a radix (Patricia) trie keyed on the
+//128 address bits, so lookups need at most one node per differing bit.
+//Trie is not safe for concurrent modification.
+type Trie[V any] struct {
+	root *trieNode[V]
+	size int
+}
+
+//TrieEntry is a prefix with its value returned by Trie queries
+type TrieEntry[V any] struct {
+	Prefix *Prefix
+	Value  V
+}
+
+//bitAt returns bit number i counted from the left (0...127)
+func bitAt(high, low uint64, i uint) int {
+	if i < 64 {
+		return int(high>>(63-i)) & 1
+	}
+	return int(low>>(127-i)) & 1
+}
+
+//commonBits returns length of the common prefix of two keys, at most max
+func commonBits(h1, l1, h2, l2 uint64, max uint) uint {
+	var n uint
+	if h1 != h2 {
+		n = uint(bits.LeadingZeros64(h1 ^ h2))
+	} else {
+		n = 64 + uint(bits.LeadingZeros64(l1^l2))
+	}
+	if n > max {
+		return max
+	}
+	return n
+}
+
+//maskBits clears host bits of a key
+func maskBits(high, low uint64, mask uint) (uint64, uint64) {
+	m, _ := AddrFromMask(mask)
+	return high & m.high, low & m.low
+}
+
+func (n *trieNode[V]) prefix() *Prefix {
+	return &Prefix{Addr{high: n.high, low: n.low}, n.mask, nil}
+}
+
+//contains tells if key with given mask lies inside the node prefix
+func (n *trieNode[V]) contains(high, low uint64, mask uint) bool {
+	return mask >= n.mask && commonBits(n.high, n.low, high, low, n.mask) == n.mask
+}
+
+//Len returns number of prefixes stored in the trie
+func (t *Trie[V]) Len() int {
+	return t.size
+}
+
+//Insert stores value for prefix, replacing previous value of the same prefix;
+//host bits of p are ignored
+func (t *Trie[V]) Insert(p *Prefix, value V) {
+	high, low := maskBits(p.addr.high, p.addr.low, p.mask)
+	mask := p.mask
+	leaf := &trieNode[V]{high: high, low: low, mask: mask, set: true, value: value}
+	link := &t.root
+	for {
+		n := *link
+		if n == nil {
+			*link = leaf
+			t.size++
+			return
+		}
+		max := n.mask
+		if mask < max {
+			max = mask
+		}
+		common := commonBits(n.high, n.low, high, low, max)
+		switch {
+		case common == n.mask && n.mask == mask:
+			if !n.set {
+				t.size++
+			}
+			n.set = true
+			n.value = value
+			return
+		case common == n.mask:
+			//n covers the new prefix, go down
+			link = &n.child[bitAt(high, low, n.mask)]
+			continue
+		case common == mask:
+			//new prefix covers n
+			leaf.child[bitAt(n.high, n.low, mask)] = n
+		default:
+			//keys diverge below both, join them with a node without value
+			gh, gl := maskBits(high, low, common)
+			glue := &trieNode[V]{high: gh, low: gl, mask: common}
+			glue.child[bitAt(high, low, common)] = leaf
+			glue.child[bitAt(n.high, n.low, common)] = n
+			leaf = glue
+		}
+		*link = leaf
+		t.size++
+		return
+	}
+}
+
+//find returns link pointing to node of exactly given prefix and links of its
+//ancestors, or nil when there is no such node
+func (t *Trie[V]) find(p *Prefix) (**trieNode[V], []**trieNode[V]) {
+	high, low := maskBits(p.addr.high, p.addr.low, p.mask)
+	path := make([]**trieNode[V], 0, 16)
+	link := &t.root
+	for *link != nil {
+		n := *link
+		if !n.contains(high, low, p.mask) {
+			return nil, nil
+		}
+		if n.mask == p.mask {
+			return link, path
+		}
+		path = append(path, link)
+		link = &n.child[bitAt(high, low, n.mask)]
+	}
+	return nil, nil
+}
+
+//Get returns value stored exactly for prefix p
+func (t *Trie[V]) Get(p *Prefix) (V, bool) {
+	high, low := maskBits(p.addr.high, p.addr.low, p.mask)
+	for n := t.root; n != nil && n.contains(high, low, p.mask); {
+		if n.mask == p.mask {
+			return n.value, n.set
+		}
+		n = n.child[bitAt(high, low, n.mask)]
+	}
+	var zero V
+	return zero, false
+}
+
+//Delete removes prefix p, returns false when it was not in the trie
+func (t *Trie[V]) Delete(p *Prefix) bool {
+	link, path := t.find(p)
+	if link == nil || !(*link).set {
+		return false
+	}
+	n := *link
+	var zero V
+	n.set = false
+	n.value = zero
+	t.size--
+	//drop nodes that no longer join two subtrees
+	for {
+		if n.set || (n.child[0] != nil && n.child[1] != nil) {
+			return true
+		}
+		if n.child[0] != nil {
+			*link = n.child[0]
+		} else {
+			*link = n.child[1]
+		}
+		if *link != nil || len(path) == 0 {
+			return true
+		}
+		link = path[len(path)-1]
+		path = path[:len(path)-1]
+		n = *link
+	}
+}
+
+//Lookup returns the longest prefix containing address a
+func (t *Trie[V]) Lookup(a *Addr) (*Prefix, V, bool) {
+	var best *trieNode[V]
+	for n := t.root; n != nil && n.contains(a.high, a.low, 128); {
+		if n.set {
+			best = n
+		}
+		if n.mask == 128 {
+			break
+		}
+		n = n.child[bitAt(a.high, a.low, n.mask)]
+	}
+	if best == nil {
+		var zero V
+		return nil, zero, false
+	}
+	return best.prefix(), best.value, true
+}
+
+//Covering returns all stored prefixes containing p (p included), shortest
+//first
+func (t *Trie[V]) Covering(p *Prefix) []TrieEntry[V] {
+	high, low := maskBits(p.addr.high, p.addr.low, p.mask)
+	ret := make([]TrieEntry[V], 0)
+	for n := t.root; n != nil && n.contains(high, low, p.mask); {
+		if n.set {
+			ret = append(ret, TrieEntry[V]{n.prefix(), n.value})
+		}
+		if n.mask == p.mask {
+			break
+		}
+		n = n.child[bitAt(high, low, n.mask)]
+	}
+	return ret
+}
+
+//Covered returns all stored prefixes inside p (p included) in address order
+func (t *Trie[V]) Covered(p *Prefix) []TrieEntry[V] {
+	high, low := maskBits(p.addr.high, p.addr.low, p.mask)
+	ret := make([]TrieEntry[V], 0)
+	n := t.root
+	//go down to the first node inside p
+	for n != nil && n.mask < p.mask {
+		if !n.contains(high, low, p.mask) {
+			return ret
+		}
+		n = n.child[bitAt(high, low, n.mask)]
+	}
+	if n == nil || commonBits(n.high, n.low, high, low, p.mask) != p.mask {
+		return ret
+	}
+	n.walk(func(p *Prefix, v V) bool {
+		ret = append(ret, TrieEntry[V]{p, v})
+		return true
+	})
+	return ret
+}
+
+//Walk calls fn for every stored prefix in address order, shorter prefixes
+//before longer ones with the same address; stops when fn returns false
+func (t *Trie[V]) Walk(fn func(p *Prefix, value V) bool) {
+	if t.root != nil {
+		t.root.walk(fn)
+	}
+}
+
+func (n *trieNode[V]) walk(fn func(p *Prefix, value V) bool) bool {
+	if n.set && !fn(n.prefix(), n.value) {
+		return false
+	}
+	for _, c := range n.child {
+		if c != nil && !c.walk(fn) {
+			return false
+		}
+	}
+	return true
+}
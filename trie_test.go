@@ -0,0 +1,330 @@
+package ipv6calc
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func mustPrefix(t testing.TB, s string) *Prefix {
+	t.Helper()
+	p, err := ParsePrefix(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func mustAddr(t testing.TB, s string) *Addr {
+	t.Helper()
+	a, err := ParseAddr(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+//checkNodes verifies that every node without value joins two subtrees and
+//returns number of nodes
+func checkNodes[V any](t *testing.T, n *trieNode[V]) int {
+	t.Helper()
+	if n == nil {
+		return 0
+	}
+	if !n.set && (n.child[0] == nil || n.child[1] == nil) {
+		t.Errorf("glue node %v has less than two children", n.prefix().CanonicalString())
+	}
+	for _, c := range n.child {
+		if c != nil && (c.mask <= n.mask || !n.contains(c.high, c.low, c.mask)) {
+			t.Errorf("child %v is not below %v", c.prefix().CanonicalString(), n.prefix().CanonicalString())
+		}
+	}
+	return 1 + checkNodes(t, n.child[0]) + checkNodes(t, n.child[1])
+}
+
+func entryStrings[V any](es []TrieEntry[V]) []string {
+	ret := make([]string, len(es))
+	for i, e := range es {
+		ret[i] = e.Prefix.CanonicalString()
+	}
+	return ret
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestTrieInsertGet(t *testing.T) {
+	tr := &Trie[int]{}
+	tr.Insert(mustPrefix(t, "2001:db8::/32"), 1)
+	tr.Insert(mustPrefix(t, "2001:db8:1::/48"), 2)
+	tr.Insert(mustPrefix(t, "2001:db8:1::1/32"), 3)
+	if tr.Len() != 2 {
+		t.Fatalf("Len() = %v, want 2", tr.Len())
+	}
+	if v, ok := tr.Get(mustPrefix(t, "2001:db8::/32")); !ok || v != 3 {
+		t.Errorf("Get(2001:db8::/32) = %v, %v, want 3, true", v, ok)
+	}
+	if _, ok := tr.Get(mustPrefix(t, "2001:db8::/33")); ok {
+		t.Errorf("Get(2001:db8::/33) found a value")
+	}
+	if _, ok := tr.Get(mustPrefix(t, "2001:db8:2::/48")); ok {
+		t.Errorf("Get(2001:db8:2::/48) found a value")
+	}
+	checkNodes(t, tr.root)
+}
+
+func TestTrieLookup(t *testing.T) {
+	tr := &Trie[string]{}
+	for _, s := range []string{"::/0", "2001:db8::/32", "2001:db8:1::/48", "2001:db8:1::1/128"} {
+		tr.Insert(mustPrefix(t, s), s)
+	}
+	tests := []struct {
+		addr string
+		want string
+	}{
+		{"2001:db8:1::1", "2001:db8:1::1/128"},
+		{"2001:db8:1::2", "2001:db8:1::/48"},
+		{"2001:db8:2::1", "2001:db8::/32"},
+		{"fe80::1", "::/0"},
+	}
+	for _, tt := range tests {
+		p, v, ok := tr.Lookup(mustAddr(t, tt.addr))
+		if !ok || v != tt.want || p.CanonicalString() != tt.want {
+			t.Errorf("Lookup(%v) = %v, %v, %v, want %v", tt.addr, p, v, ok, tt.want)
+		}
+	}
+	tr.Delete(mustPrefix(t, "::/0"))
+	if _, _, ok := tr.Lookup(mustAddr(t, "fe80::1")); ok {
+		t.Errorf("Lookup(fe80::1) matched after deleting ::/0")
+	}
+}
+
+func TestTrieDelete(t *testing.T) {
+	tr := &Trie[int]{}
+	//2001:db8::/46 and 2001:db8:4::/48 hang below a glue node 2001:db8::/45
+	//which goes away together with one of them
+	for i, s := range []string{"2001:db8::/32", "2001:db8::/46", "2001:db8:4::/48", "2001:db8:4:1::/64"} {
+		tr.Insert(mustPrefix(t, s), i)
+	}
+	if n := checkNodes(t, tr.root); n != 5 {
+		t.Fatalf("%v nodes after inserts, want 5", n)
+	}
+	if tr.Delete(mustPrefix(t, "2001:db8::/45")) {
+		t.Errorf("Delete of glue node 2001:db8::/45 succeeded")
+	}
+	if tr.Delete(mustPrefix(t, "2001:db8:5::/48")) {
+		t.Errorf("Delete of missing prefix succeeded")
+	}
+
+	//node with one child is spliced out
+	if !tr.Delete(mustPrefix(t, "2001:db8:4::/48")) {
+		t.Fatalf("Delete(2001:db8:4::/48) failed")
+	}
+	if n := checkNodes(t, tr.root); n != 4 {
+		t.Errorf("%v nodes after splicing a node, want 4", n)
+	}
+	//leaf is removed and its glue parent spliced out
+	if !tr.Delete(mustPrefix(t, "2001:db8::/46")) {
+		t.Fatalf("Delete(2001:db8::/46) failed")
+	}
+	if n := checkNodes(t, tr.root); n != 2 {
+		t.Errorf("%v nodes after removing a leaf, want 2", n)
+	}
+	if v, ok := tr.Get(mustPrefix(t, "2001:db8:4:1::/64")); !ok || v != 3 {
+		t.Errorf("Get(2001:db8:4:1::/64) = %v, %v after deletes", v, ok)
+	}
+	if tr.Delete(mustPrefix(t, "2001:db8::/46")) {
+		t.Errorf("second Delete(2001:db8::/46) succeeded")
+	}
+
+	tr.Delete(mustPrefix(t, "2001:db8::/32"))
+	tr.Delete(mustPrefix(t, "2001:db8:4:1::/64"))
+	if tr.root != nil || tr.Len() != 0 {
+		t.Errorf("trie not empty after deleting everything, Len() = %v", tr.Len())
+	}
+}
+
+func TestTrieCoveringCovered(t *testing.T) {
+	tr := &Trie[int]{}
+	for i, s := range []string{"2001:db8::/32", "2001:db8::/48", "2001:db8:0:1::/64", "2001:db8:1::/48", "2001:db9::/32", "::/0"} {
+		tr.Insert(mustPrefix(t, s), i)
+	}
+	tests := []struct {
+		prefix   string
+		covering []string
+		covered  []string
+	}{
+		{"2001:db8::/48",
+			[]string{"::/0", "2001:db8::/32", "2001:db8::/48"},
+			[]string{"2001:db8::/48", "2001:db8:0:1::/64"}},
+		{"2001:db8::/31",
+			[]string{"::/0"},
+			[]string{"2001:db8::/32", "2001:db8::/48", "2001:db8:0:1::/64", "2001:db8:1::/48", "2001:db9::/32"}},
+		{"2001:db8:0:2::/64",
+			[]string{"::/0", "2001:db8::/32", "2001:db8::/48"},
+			[]string{}},
+		{"2001:db8:8000::/33",
+			[]string{"::/0", "2001:db8::/32"},
+			[]string{}},
+	}
+	for _, tt := range tests {
+		p := mustPrefix(t, tt.prefix)
+		if got := entryStrings(tr.Covering(p)); !equalStrings(got, tt.covering) {
+			t.Errorf("Covering(%v) = %v, want %v", tt.prefix, got, tt.covering)
+		}
+		if got := entryStrings(tr.Covered(p)); !equalStrings(got, tt.covered) {
+			t.Errorf("Covered(%v) = %v, want %v", tt.prefix, got, tt.covered)
+		}
+	}
+}
+
+func TestTrieRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	tr := &Trie[int]{}
+	stored := make(map[string]*Prefix)
+	//few short prefixes in a small space, so they nest and share paths
+	random := func() *Prefix {
+		a := Addr{high: 0x20010db800000000 | r.Uint64()&0xff00ff00, low: uint64(r.Intn(4))}
+		return (&Prefix{a, 32 + uint(r.Intn(33)), nil}).MakeSubnetAddress()
+	}
+	for i := 0; i < 2000; i++ {
+		p := random()
+		key := p.CanonicalString()
+		if r.Intn(3) == 0 {
+			_, ok := stored[key]
+			if tr.Delete(p) != ok {
+				t.Fatalf("Delete(%v) disagrees with map", key)
+			}
+			delete(stored, key)
+		} else {
+			tr.Insert(p, i)
+			stored[key] = p
+		}
+	}
+	if tr.Len() != len(stored) {
+		t.Fatalf("Len() = %v, want %v", tr.Len(), len(stored))
+	}
+	checkNodes(t, tr.root)
+	for i := 0; i < 500; i++ {
+		q := random()
+		want := 0
+		for _, p := range stored {
+			if p.ContainsPrefix(q) {
+				want++
+			}
+		}
+		if got := len(tr.Covering(q)); got != want {
+			t.Errorf("Covering(%v) returned %v prefixes, want %v", q.CanonicalString(), got, want)
+		}
+	}
+}
+
+//lengths roughly follow distribution of prefix lengths in the IPv6 DFZ
+var benchLengths = []struct {
+	mask   uint
+	weight int
+}{{48, 50}, {32, 12}, {44, 8}, {40, 7}, {36, 5}, {29, 4}, {46, 4}, {47, 3}, {42, 3}, {33, 2}, {28, 1}, {64, 1}}
+
+//benchTable returns n random global unicast prefixes shaped like the IPv6
+//BGP table and addresses to look up, half of them inside stored prefixes
+func benchTable(n int) ([]*Prefix, []Addr) {
+	total := 0
+	for _, l := range benchLengths {
+		total += l.weight
+	}
+	r := rand.New(rand.NewSource(1))
+	table := make([]*Prefix, n)
+	for i := range table {
+		w := r.Intn(total)
+		mask := benchLengths[0].mask
+		for _, l := range benchLengths {
+			if w < l.weight {
+				mask = l.mask
+				break
+			}
+			w -= l.weight
+		}
+		a := Addr{high: 0x2000000000000000 | r.Uint64()>>3, low: r.Uint64()}
+		table[i] = (&Prefix{a, mask, nil}).MakeSubnetAddress()
+	}
+	addrs := make([]Addr, 1<<16)
+	for i := range addrs {
+		if i%2 == 0 {
+			p := table[r.Intn(len(table))]
+			first, last := p.FirstAddressFromSubnet(), p.LastAddressFromSubnet()
+			addrs[i] = Addr{high: first.high | r.Uint64()&last.high, low: first.low | r.Uint64()&last.low}
+		} else {
+			addrs[i] = Addr{high: 0x2000000000000000 | r.Uint64()>>3, low: r.Uint64()}
+		}
+	}
+	return table, addrs
+}
+
+func benchTrie(b *testing.B) (*Trie[int], []*Prefix, []Addr) {
+	table, addrs := benchTable(200000)
+	tr := &Trie[int]{}
+	for i, p := range table {
+		tr.Insert(p, i)
+	}
+	b.ResetTimer()
+	return tr, table, addrs
+}
+
+func BenchmarkTrieInsert(b *testing.B) {
+	table, _ := benchTable(200000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	tr := &Trie[int]{}
+	for i := 0; i < b.N; i++ {
+		if i%len(table) == 0 {
+			tr = &Trie[int]{}
+		}
+		tr.Insert(table[i%len(table)], i)
+	}
+}
+
+func BenchmarkTrieLookup(b *testing.B) {
+	tr, _, addrs := benchTrie(b)
+	for i := 0; i < b.N; i++ {
+		tr.Lookup(&addrs[i&(len(addrs)-1)])
+	}
+}
+
+func BenchmarkTrieGet(b *testing.B) {
+	tr, table, _ := benchTrie(b)
+	for i := 0; i < b.N; i++ {
+		tr.Get(table[i%len(table)])
+	}
+}
+
+func BenchmarkTrieCovering(b *testing.B) {
+	tr, table, _ := benchTrie(b)
+	for i := 0; i < b.N; i++ {
+		tr.Covering(table[i%len(table)])
+	}
+}
+
+func BenchmarkTrieDelete(b *testing.B) {
+	table, _ := benchTable(200000)
+	b.ResetTimer()
+	var tr *Trie[int]
+	for i := 0; i < b.N; i++ {
+		if i%len(table) == 0 {
+			b.StopTimer()
+			tr = &Trie[int]{}
+			for j, p := range table {
+				tr.Insert(p, j)
+			}
+			b.StartTimer()
+		}
+		tr.Delete(table[i%len(table)])
+	}
+}
@@ -0,0 +1,60 @@
+package ipv6calc
+
+import "strings"
+
+//Zone returns zone identifier (interface name or index) of the address,
+//empty when there is none
+func (i6 *Addr) Zone() string {
+	return i6.zone
+}
+
+//WithZone returns copy of the address with zone identifier replaced
+func (i6 *Addr) WithZone(zone string) *Addr {
+	return &Addr{i6.high, i6.low, zone}
+}
+
+func (i6 *Addr) zoneSuffix() string {
+	if len(i6.zone) == 0 {
+		return ""
+	}
+	return "%" + i6.zone
+}
+
+//zoneScoped tells if zone identifier has a meaning for the address:
+//link-local unicast fe80::/10 and interface-local or link-local multicast
+func (i6 *Addr) zoneScoped() bool {
+	if i6.high>>54 == 0xfe80>>6 {
+		return true
+	}
+	if i6.high>>56 == 0xff {
+		scope := (i6.high >> 48) & 0xf
+		return scope == 1 || scope == 2
+	}
+	return false
+}
+
+//ParseAddrStrict works like ParseAddr, but rejects zone identifiers on
+//addresses that are not link-local
+func ParseAddrStrict(s string) (*Addr, error) {
+	i6, err := ParseAddr(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(i6.zone) > 0 && !i6.zoneScoped() {
+		return nil, newParseError(s, strings.IndexByte(s, '%'), ErrZoneNotAllowed)
+	}
+	return i6, nil
+}
+
+//ParsePrefixStrict works like ParsePrefix, but rejects zone identifiers on
+//addresses that are not link-local
+func ParsePrefixStrict(s string) (*Prefix, error) {
+	p, err := ParsePrefix(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.addr.zone) > 0 && !p.addr.zoneScoped() {
+		return nil, newParseError(s, strings.IndexByte(s, '%'), ErrZoneNotAllowed)
+	}
+	return p, nil
+}